@@ -0,0 +1,204 @@
+package bine
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// assetPlatform describes the platform a release asset is selected for when
+// the bin has no asset pattern. The uname values and the rustc target triple
+// are only known for the current platform.
+type assetPlatform struct {
+	goos      string
+	goarch    string
+	unameOS   string
+	unameArch string
+	triple    string
+}
+
+// assetPlatform returns the description of the given platform, e.g.
+// "linux/amd64".
+func (n *namer) assetPlatform(platform string) (*assetPlatform, error) {
+	targetOS, targetArch, ok := strings.Cut(platform, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid platform %q", platform)
+	}
+
+	p := &assetPlatform{goos: targetOS, goarch: targetArch}
+	if n != nil && targetOS == goos && targetArch == goarch {
+		p.unameOS = n.unameOS
+		p.unameArch = n.unameArch
+		p.triple = n.triple
+	}
+
+	return p, nil
+}
+
+func (p *assetPlatform) String() string {
+	return p.goos + "/" + p.goarch
+}
+
+// Aliases of the operating systems and architectures found in asset names.
+var (
+	assetOSAliases = map[string][]string{
+		"darwin":  {"darwin", "macos", "osx", "mac", "apple"},
+		"windows": {"windows", "win", "win64", "win32"},
+	}
+	assetArchAliases = map[string][]string{
+		"amd64":   {"amd64", "x64", "64bit"},
+		"386":     {"386", "i386", "i686", "x86", "32bit"},
+		"arm64":   {"arm64", "aarch64"},
+		"arm":     {"arm", "armv7", "armv7l", "armv6", "armhf"},
+		"ppc64le": {"ppc64le", "powerpc64le"},
+		"ppc64":   {"ppc64", "powerpc64"},
+	}
+	// assetUniversalArch are the names of macOS builds for every architecture.
+	assetUniversalArch = []string{"universal", "all"}
+)
+
+// Suffixes of the files that release alongside binaries but are not binaries.
+var assetIgnoredSuffixes = []string{
+	".sha256", ".sha256sum", ".sha512", ".sha512sum", ".md5", ".sig", ".asc",
+	".pem", ".cert", ".crt", ".sbom", ".spdx", ".json", ".jsonl", ".txt",
+	".deb", ".rpm", ".apk", ".msi", ".pkg", ".dmg", ".snap", ".appimage",
+	".sh", ".yaml", ".yml",
+}
+
+// Preference of the archive formats, raw binaries have no suffix.
+var assetFormats = []struct {
+	suffix string
+	score  int
+}{
+	{".tar.gz", 4},
+	{".tgz", 4},
+	{".zip", 3},
+	{".tar.xz", 2},
+	{".tar.zst", 2},
+	{".tar.bz2", 1},
+	{".gz", 1},
+}
+
+// containsWord reports whether name contains word delimited by characters
+// other than letters and digits.
+func containsWord(name, word string) bool {
+	re := regexp.MustCompile(`(^|[^a-z0-9])` + regexp.QuoteMeta(word) + `([^a-z0-9]|$)`)
+	return re.MatchString(name)
+}
+
+func containsAnyWord(name string, words []string) bool {
+	return slices.ContainsFunc(words, func(w string) bool { return containsWord(name, w) })
+}
+
+// assetScore rates how well the asset name fits the platform. It returns false
+// when the asset can't be used on the platform.
+func assetScore(b *bin, name string, p *assetPlatform) (int, bool) {
+	lower := strings.ToLower(name)
+	for _, suffix := range assetIgnoredSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return 0, false
+		}
+	}
+	if strings.Contains(lower, "checksums") || strings.Contains(lower, "sha256sums") {
+		return 0, false
+	}
+
+	score := 0
+	if p.triple != "" && strings.Contains(lower, strings.ToLower(p.triple)) {
+		score += 4
+	}
+
+	// "x86_64" would otherwise match the "x86" alias of 386.
+	lower = strings.NewReplacer("x86_64", "x64", "x86-64", "x64").Replace(lower)
+
+	osAliases := append([]string{p.goos}, assetOSAliases[p.goos]...)
+	if p.unameOS != "" {
+		osAliases = append(osAliases, strings.ToLower(p.unameOS))
+	}
+	if !containsAnyWord(lower, osAliases) {
+		return 0, false
+	}
+
+	archAliases := append([]string{p.goarch}, assetArchAliases[p.goarch]...)
+	if p.unameArch != "" {
+		archAliases = append(archAliases, strings.ToLower(strings.NewReplacer("x86_64", "x64").Replace(p.unameArch)))
+	}
+	switch {
+	case containsAnyWord(lower, archAliases):
+		score += 2
+	case p.goos == "darwin" && containsAnyWord(lower, assetUniversalArch):
+		score++
+	default:
+		return 0, false
+	}
+
+	// musl binaries are usually static and run anywhere, but glibc binaries
+	// don't run on musl systems.
+	if p.goos == "linux" {
+		musl := strings.HasSuffix(p.triple, "-musl")
+		switch {
+		case containsWord(lower, "musl") && musl:
+			score += 2
+		case containsWord(lower, "gnu") && musl:
+			score -= 2
+		case containsWord(lower, "gnu"):
+			score++
+		}
+	}
+
+	isWindowsExe := strings.HasSuffix(lower, ".exe")
+	switch {
+	case isWindowsExe && p.goos != "windows":
+		return 0, false
+	case isWindowsExe:
+		score += 5
+	default:
+		format := 3 // Raw binaries.
+		for _, f := range assetFormats {
+			if strings.HasSuffix(lower, f.suffix) {
+				format = f.score
+				break
+			}
+		}
+		// zip archives are the norm on Windows.
+		if p.goos == "windows" && strings.HasSuffix(lower, ".zip") {
+			format += 3
+		}
+		score += format
+	}
+
+	if containsWord(lower, strings.ToLower(b.Name)) {
+		score++
+	}
+
+	return score, true
+}
+
+// selectAsset picks the release asset that fits the platform best. It fails
+// when no asset fits or when several fit equally well.
+func selectAsset(b *bin, names []string, p *assetPlatform) (string, error) {
+	var best []string
+	bestScore := 0
+	for _, name := range names {
+		score, ok := assetScore(b, name, p)
+		switch {
+		case !ok:
+			continue
+		case len(best) == 0 || score > bestScore:
+			best, bestScore = []string{name}, score
+		case score == bestScore:
+			best = append(best, name)
+		}
+	}
+
+	switch len(best) {
+	case 0:
+		return "", fmt.Errorf("no release asset matches %s, set asset_pattern", p)
+	case 1:
+		return best[0], nil
+	default:
+		slices.Sort(best)
+		return "", fmt.Errorf("several release assets match %s (%s), set asset_pattern", p, strings.Join(best, ", "))
+	}
+}
@@ -0,0 +1,136 @@
+package bine
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSelectAsset(t *testing.T) {
+	goreleaser := []string{
+		"tool_1.0.0_checksums.txt",
+		"tool_1.0.0_darwin_all.tar.gz",
+		"tool_1.0.0_linux_amd64.deb",
+		"tool_1.0.0_linux_amd64.tar.gz",
+		"tool_1.0.0_linux_amd64.tar.gz.sbom.json",
+		"tool_1.0.0_linux_arm64.tar.gz",
+		"tool_1.0.0_windows_amd64.zip",
+	}
+	rust := []string{
+		"tool-aarch64-apple-darwin.tar.gz",
+		"tool-aarch64-apple-darwin.tar.gz.sha256",
+		"tool-x86_64-pc-windows-msvc.zip",
+		"tool-x86_64-unknown-linux-gnu.tar.gz",
+		"tool-x86_64-unknown-linux-musl.tar.gz",
+		"tool-i686-unknown-linux-gnu.tar.gz",
+	}
+	uname := []string{
+		"tool_Darwin_arm64.tar.gz",
+		"tool_Linux_i386.tar.gz",
+		"tool_Linux_x86_64.tar.gz",
+	}
+
+	tests := []struct {
+		name     string
+		assets   []string
+		platform assetPlatform
+		want     string
+		err      string
+	}{
+		{
+			name:     "Picks the archive of the platform",
+			assets:   goreleaser,
+			platform: assetPlatform{goos: "linux", goarch: "amd64"},
+			want:     "tool_1.0.0_linux_amd64.tar.gz",
+		},
+		{
+			name:     "Picks universal macOS builds",
+			assets:   goreleaser,
+			platform: assetPlatform{goos: "darwin", goarch: "arm64"},
+			want:     "tool_1.0.0_darwin_all.tar.gz",
+		},
+		{
+			name:     "Picks zip archives on Windows",
+			assets:   goreleaser,
+			platform: assetPlatform{goos: "windows", goarch: "amd64"},
+			want:     "tool_1.0.0_windows_amd64.zip",
+		},
+		{
+			name:     "Prefers glibc builds",
+			assets:   rust,
+			platform: assetPlatform{goos: "linux", goarch: "amd64"},
+			want:     "tool-x86_64-unknown-linux-gnu.tar.gz",
+		},
+		{
+			name:     "Prefers musl builds on musl systems",
+			assets:   rust,
+			platform: assetPlatform{goos: "linux", goarch: "amd64", triple: "x86_64-unknown-linux-musl"},
+			want:     "tool-x86_64-unknown-linux-musl.tar.gz",
+		},
+		{
+			name:     "Prefers the rustc target triple",
+			assets:   []string{"tool-x86_64-unknown-linux-gnu.zip", "tool-linux-amd64.tar.gz"},
+			platform: assetPlatform{goos: "linux", goarch: "amd64", triple: "x86_64-unknown-linux-gnu"},
+			want:     "tool-x86_64-unknown-linux-gnu.zip",
+		},
+		{
+			name:     "Matches Apple triples",
+			assets:   rust,
+			platform: assetPlatform{goos: "darwin", goarch: "arm64"},
+			want:     "tool-aarch64-apple-darwin.tar.gz",
+		},
+		{
+			name:     "Doesn't confuse x86_64 with x86",
+			assets:   rust,
+			platform: assetPlatform{goos: "linux", goarch: "386"},
+			want:     "tool-i686-unknown-linux-gnu.tar.gz",
+		},
+		{
+			name:     "Matches uname values",
+			assets:   uname,
+			platform: assetPlatform{goos: "linux", goarch: "amd64", unameOS: "Linux", unameArch: "x86_64"},
+			want:     "tool_Linux_x86_64.tar.gz",
+		},
+		{
+			name:     "Prefers archives over raw binaries",
+			assets:   []string{"tool-linux-amd64", "tool-linux-amd64.tar.gz", "tool-linux-amd64.tar.bz2"},
+			platform: assetPlatform{goos: "linux", goarch: "amd64"},
+			want:     "tool-linux-amd64.tar.gz",
+		},
+		{
+			name:     "Picks .exe binaries on Windows",
+			assets:   []string{"tool-win64-x64.exe", "tool-win64-x64.tar.gz", "tool-linux-x64"},
+			platform: assetPlatform{goos: "windows", goarch: "amd64"},
+			want:     "tool-win64-x64.exe",
+		},
+		{
+			name:     "Skips .exe binaries on other platforms",
+			assets:   []string{"tool-linux-amd64.exe", "tool-linux-amd64"},
+			platform: assetPlatform{goos: "linux", goarch: "amd64"},
+			want:     "tool-linux-amd64",
+		},
+		{
+			name:     "Fails without matching assets",
+			assets:   goreleaser,
+			platform: assetPlatform{goos: "freebsd", goarch: "amd64"},
+			err:      "no release asset matches freebsd/amd64, set asset_pattern",
+		},
+		{
+			name:     "Fails when several assets match",
+			assets:   []string{"tool-linux-amd64.tar.gz", "tool-server-linux-amd64.tar.gz", "tool-linux-arm64.tar.gz"},
+			platform: assetPlatform{goos: "linux", goarch: "amd64"},
+			err:      "several release assets match linux/amd64 (tool-linux-amd64.tar.gz, tool-server-linux-amd64.tar.gz), set asset_pattern",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectAsset(&bin{Name: "tool"}, tc.assets, &tc.platform)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
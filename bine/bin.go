@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"regexp"
 	"strings"
 
@@ -22,12 +22,73 @@ type bin struct {
 	URL          string `json:"url,omitempty" toml:"url,omitempty"`
 	AssetPattern string `json:"asset_pattern,omitempty" toml:"asset_pattern,omitempty"`
 
+	// Private is set when the releases can't be downloaded without a token.
+	Private bool `json:"private,omitempty" toml:"private,omitempty"`
+
+	// Provider of the releases, e.g. "gitlab" or "gitea". Guessed from URL if
+	// empty.
+	Provider string `json:"provider,omitempty" toml:"provider,omitempty"`
+
+	// Settings passed along to external provider plugins.
+	ProviderOptions map[string]string `json:"provider_options,omitempty" toml:"provider_options,omitempty"`
+
+	// Fields for downloads from arbitrary web servers. DownloadURL supports
+	// the same variables as AssetPattern.
+	DownloadURL string `json:"download_url,omitempty" toml:"download_url,omitempty"`
+
+	// Page listing the available versions, used to find the latest one with
+	// either VersionsRegex or VersionsJSONPath.
+	VersionsURL      string `json:"versions_url,omitempty" toml:"versions_url,omitempty"`
+	VersionsRegex    string `json:"versions_regex,omitempty" toml:"versions_regex,omitempty"`
+	VersionsJSONPath string `json:"versions_json_path,omitempty" toml:"versions_json_path,omitempty"`
+
 	// Template for tag formatting. Supports {version} placeholder.
 	// Defaults to "v{version}" if not specified.
 	TagPattern string `json:"tag_pattern,omitempty" toml:"tag_pattern,omitempty"`
 
+	// Releases considered when looking for the latest version: "stable", the
+	// default, or "prerelease" to include release candidates and the like.
+	Channel string `json:"channel,omitempty" toml:"channel,omitempty"`
+
+	// How versions are compared when looking for the latest one: "semver",
+	// the default, "calver", "numeric", "lexical" or a regular expression
+	// whose capture groups are compared in order.
+	VersionScheme string `json:"version_scheme,omitempty" toml:"version_scheme,omitempty"`
+
+	// Name of the checksum file published alongside the asset, e.g.
+	// "checksums.txt". Supports the same variables as AssetPattern.
+	ChecksumPattern string `json:"checksum_pattern,omitempty" toml:"checksum_pattern,omitempty"`
+
+	// Detached signature published alongside the asset.
+	Signature *binSignature `json:"signature,omitempty" toml:"signature,omitempty"`
+
+	// Expected digests of the asset keyed by platform, e.g. "linux/amd64":
+	// "sha256:...". Rewritten by upgrades.
+	Checksums map[string]string `json:"checksums,omitempty" toml:"checksums,omitempty"`
+
+	// Fields for builds from source: the shell command that builds the
+	// binary in the Git repository and the path of the binary it produces,
+	// which defaults to the name of the bin.
+	Build       string `json:"build,omitempty" toml:"build,omitempty"`
+	BuildOutput string `json:"build_output,omitempty" toml:"build_output,omitempty"`
+
+	// Field for binaries extracted from container images: the path of the
+	// binary in the image, which defaults to "/usr/local/bin/{name}".
+	ImagePath string `json:"image_path,omitempty" toml:"image_path,omitempty"`
+
 	// Field for go-based installs.
 	GoPackage string `json:"go_package,omitempty" toml:"go_package,omitempty"`
+	// GoMod installs the version of the module required by the go.mod file
+	// next to the configuration file instead of Version.
+	GoMod bool `json:"go_mod,omitempty" toml:"go_mod,omitempty"`
+
+	// Build settings of go-based installs: extra environment variables as
+	// KEY=VALUE pairs, build tags, linker flags and whether to trim paths.
+	// Changing them triggers a reinstall.
+	GoEnv     []string `json:"go_env,omitempty" toml:"go_env,omitempty"`
+	GoTags    []string `json:"go_tags,omitempty" toml:"go_tags,omitempty"`
+	GoLdflags string   `json:"go_ldflags,omitempty" toml:"go_ldflags,omitempty"`
+	Trimpath  bool     `json:"trimpath,omitempty" toml:"trimpath,omitempty"`
 
 	// Allows to apply modifications during variable expansion.
 	Modifiers map[string]map[string]string `json:"modifiers,omitempty" toml:"modifiers,omitempty"`
@@ -35,29 +96,116 @@ type bin struct {
 	// asset is computed by the namer when the config is loaded.
 	asset string
 
+	// checksumAsset is computed by the namer when the config is loaded.
+	checksumAsset string
+
+	// signatureAsset is computed by the namer when the config is loaded.
+	signatureAsset string
+
+	// expandedDownloadURL is computed by the namer when the config is loaded.
+	expandedDownloadURL string
+
+	// autoAsset is set by the namer when the bin has no asset pattern, so the
+	// provider selects the release asset that fits the platform.
+	autoAsset *assetPlatform
+
+	// includePrereleases is set when prereleases are considered for every
+	// bin, regardless of the channel.
+	includePrereleases bool
+
+	// constraint is parsed from Version when it's a range, e.g. "^1.62".
+	constraint *versionConstraint
+
+	// scheme is parsed from VersionScheme when the provider is loaded.
+	scheme *versionScheme
+
 	provider binProvider
 }
 
+const (
+	channelStable     = "stable"
+	channelPrerelease = "prerelease"
+)
+
+// prereleases reports whether prereleases are considered when looking for
+// the latest version.
+func (b bin) prereleases() bool {
+	return b.Channel == channelPrerelease || b.includePrereleases
+}
+
+// versionScheme returns the scheme used to compare the versions of the
+// binary, semver unless configured otherwise.
+func (b bin) versionScheme() *versionScheme {
+	if b.scheme == nil {
+		return defaultVersionScheme
+	}
+	return b.scheme
+}
+
+// considers reports whether latest-version checks consider the version, which
+// must be in the range of bins with a version range. Only semver versions
+// have prereleases.
+func (b bin) considers(version string) bool {
+	if scheme := b.versionScheme(); !scheme.semver() {
+		return scheme.valid(version)
+	}
+	canonical := semver.Canonical("v" + strings.TrimPrefix(version, "v"))
+	if canonical == "" || semver.Prerelease(canonical) != "" && !b.prereleases() {
+		return false
+	}
+	return b.constraint == nil || b.constraint.check(canonical)
+}
+
+// versionKind describes the versions considered when looking for the latest
+// version, used in error messages.
+func (b bin) versionKind() string {
+	if scheme := b.versionScheme(); !scheme.semver() {
+		return scheme.kind()
+	}
+	if b.prereleases() {
+		return "semver"
+	}
+	return "non-prerelease semver"
+}
+
 func (b bin) goPkg() bool {
 	return b.GoPackage != ""
 }
 
 // isLatest returns true if this binary tracks the latest available version.
-// Only applicable to Go packages.
+// Go packages also track it when the version is omitted.
 func (b bin) isLatest() bool {
 	if !b.goPkg() {
-		return false
+		return strings.EqualFold(b.Version, "latest")
 	}
 	return b.Version == "" || strings.EqualFold(b.Version, "latest")
 }
 
+// isRange returns true if the version of this binary is a range, e.g. "^1.62",
+// resolved to the latest version in range when the binary is installed.
+func (b bin) isRange() bool {
+	return b.constraint != nil
+}
+
+// resolvesVersion returns true if bine resolves the version installed for
+// this binary before expanding its asset names: for version ranges and for
+// asset-based binaries tracking the latest version. "go install" resolves the
+// latest version of Go packages by itself.
+func (b bin) resolvesVersion() bool {
+	return b.isRange() || b.isLatest() && !b.goPkg()
+}
+
 // markerVersion returns the version string used for the version marker file.
 // For "latest" bins, always returns "latest" regardless of whether the version
-// field is empty or explicitly set to "latest".
+// field is empty or explicitly set to "latest". Ranges are escaped since they
+// contain characters that aren't valid in file names.
 func (b bin) markerVersion() string {
 	if b.isLatest() {
 		return "latest"
 	}
+	if b.isRange() {
+		return url.PathEscape(b.Version)
+	}
 	return b.Version
 }
 
@@ -79,13 +227,48 @@ func (b bin) unprefixedVersion() string {
 // usableVersion falls back to the original version if semver is not available.
 // Useful in contexts where semver is not required, e.g. during downloads.
 func (b bin) usableVersion() string {
-	version := b.canonicalVersion()
-	if version == "" {
-		return b.Version
+	return b.displayVersion(b.Version)
+}
+
+// displayVersion returns the canonical formatting of the version if the
+// binary uses semver and the version is valid, or the version otherwise.
+func (b bin) displayVersion(version string) string {
+	if !b.versionScheme().semver() {
+		return version
+	}
+	if canonical := semver.Canonical("v" + strings.TrimPrefix(version, "v")); canonical != "" {
+		return canonical
 	}
 	return version
 }
 
+// assetPattern returns the pattern of the asset name. When DownloadURL is
+// used, the asset is named after the last element of its path.
+func (b bin) assetPattern() string {
+	if b.DownloadURL != "" {
+		u, _, _ := strings.Cut(b.DownloadURL, "?")
+		return path.Base(u)
+	}
+	return b.AssetPattern
+}
+
+// buildOutput returns the path of the binary produced by the build recipe,
+// relative to the root of the repository.
+func (b bin) buildOutput() string {
+	if b.BuildOutput == "" {
+		return b.Name
+	}
+	return strings.ReplaceAll(b.BuildOutput, "{name}", b.Name)
+}
+
+// imagePath returns the path of the binary in the container image.
+func (b bin) imagePath() string {
+	if b.ImagePath == "" {
+		return "/usr/local/bin/" + b.Name
+	}
+	return strings.ReplaceAll(b.ImagePath, "{name}", b.Name)
+}
+
 func (b bin) tagPattern() string {
 	if b.TagPattern == "" {
 		return "v{version}"
@@ -102,18 +285,173 @@ func (b bin) tag() string {
 	return template
 }
 
-func (b *bin) loadProvider(client *http.Client, ghAPIToken string) error {
+const (
+	providerGitHub = "github"
+	providerGitLab = "gitlab"
+	providerGitea  = "gitea"
+	providerHTTP   = "http"
+	providerSource = "source"
+	providerOCI    = "oci"
+)
+
+// githubAPIURL is the URL of the REST API of github.com.
+const githubAPIURL = "https://api.github.com"
+
+// providerOptions holds the settings shared by the binary providers.
+type providerOptions struct {
+	client     *http.Client
+	ghAPIToken string
+	glAPIToken string
+
+	// ghAPIURL is the URL of the REST API of GitHub Enterprise Server, used
+	// for repositories not hosted on github.com.
+	ghAPIURL string
+	// ghHostTokens are the GitHub tokens keyed by host, e.g. "ghe.corp".
+	ghHostTokens map[string]string
+
+	// includePrereleases considers prereleases of every bin when looking for
+	// the latest version.
+	includePrereleases bool
+
+	// ghReleasePages is the maximum number of pages of GitHub releases read
+	// when looking for the latest version.
+	ghReleasePages int
+	// ghReleases holds the GitHub releases fetched ahead of latest-version
+	// checks, shared by the GitHub providers.
+	ghReleases *githubReleaseCache
+}
+
+// isGitHubEnterprise reports whether repoURL is hosted on the GitHub
+// Enterprise Server instance configured with ghAPIURL.
+func (o providerOptions) isGitHubEnterprise(repoURL string) bool {
+	if o.ghAPIURL == "" {
+		return false
+	}
+	api, err := url.Parse(o.ghAPIURL)
+	if err != nil {
+		return false
+	}
+	repo, err := url.Parse(repoURL)
+	if err != nil {
+		return false
+	}
+	return repo.Host != "" && repo.Host == api.Host
+}
+
+// githubAPI returns the API URL and the token used for the GitHub repository
+// at repoURL. Repositories hosted elsewhere than github.com are assumed to be
+// on GitHub Enterprise Server, which serves the API under "/api/v3" unless
+// ghAPIURL says otherwise. The token given for github.com is never sent to
+// other hosts.
+func (o providerOptions) githubAPI(repoURL string) (string, string, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", fmt.Errorf("parse URL: %v", err)
+	}
+
+	if u.Host == "" || u.Host == "github.com" || u.Host == "www.github.com" {
+		if token, ok := o.ghHostTokens["github.com"]; ok {
+			return githubAPIURL, token, nil
+		}
+		return githubAPIURL, o.ghAPIToken, nil
+	}
+
+	apiURL := o.ghAPIURL
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("%s://%s/api/v3", u.Scheme, u.Host)
+	}
+
+	return strings.TrimSuffix(apiURL, "/"), o.ghHostTokens[u.Host], nil
+}
+
+func (b *bin) loadProvider(opts providerOptions) error {
 	if b.provider != nil {
 		return nil
 	}
 
+	switch b.Channel {
+	case "", channelStable, channelPrerelease:
+	default:
+		return fmt.Errorf("invalid channel %q, expected %q or %q", b.Channel, channelStable, channelPrerelease)
+	}
+	b.includePrereleases = opts.includePrereleases
+
+	scheme, err := parseVersionScheme(b.VersionScheme)
+	if err != nil {
+		return err
+	}
+	if !scheme.semver() && b.goPkg() {
+		return fmt.Errorf("version_scheme %q cannot be used with Go packages", b.VersionScheme)
+	}
+	b.scheme = scheme
+
+	if !b.isLatest() && scheme.semver() && isVersionConstraint(b.Version) {
+		constraint, err := parseVersionConstraint(b.Version)
+		if err != nil {
+			return err
+		}
+		b.constraint = constraint
+	}
+	if b.resolvesVersion() && len(b.Checksums) > 0 {
+		return fmt.Errorf("checksums cannot be pinned for version %q", b.Version)
+	}
+
 	switch {
 	case b.goPkg():
-		b.provider = &goProvider{client: client}
-	case strings.Contains(b.URL, "github.com"):
-		b.provider = &githubProvider{client: client, token: ghAPIToken}
-	case strings.Contains(b.URL, "release.ariga.io"):
-		b.provider = &arigaProvider{client: client, token: ghAPIToken}
+		b.provider = &goProvider{client: opts.client}
+	case b.Provider == providerGitHub || b.Provider == "" && (strings.Contains(b.URL, "github.com") || opts.isGitHubEnterprise(b.URL)):
+		apiURL, token, err := opts.githubAPI(b.URL)
+		if err != nil {
+			return err
+		}
+		b.provider = &githubProvider{
+			client:   opts.client,
+			token:    token,
+			apiURL:   apiURL,
+			pages:    opts.ghReleasePages,
+			releases: opts.ghReleases,
+		}
+	case b.Provider == providerGitLab || b.Provider == "" && strings.Contains(b.URL, "gitlab.com"):
+		provider, err := newGitLabProvider(opts.client, opts.glAPIToken, b.URL)
+		if err != nil {
+			return err
+		}
+		b.provider = provider
+	case b.Provider == providerGitea || b.Provider == "" && strings.Contains(b.URL, "codeberg.org"):
+		provider, err := newGiteaProvider(opts.client, b.URL)
+		if err != nil {
+			return err
+		}
+		b.provider = provider
+	case b.Provider == providerHTTP || b.Provider == "" && b.DownloadURL != "":
+		provider, err := newHTTPProvider(opts.client, b)
+		if err != nil {
+			return err
+		}
+		b.provider = provider
+	case b.Provider == "" && strings.Contains(b.URL, "release.ariga.io"):
+		// Atlas publishes its releases on github.com.
+		_, token, _ := opts.githubAPI("https://github.com/ariga/atlas")
+		b.provider = &arigaProvider{client: opts.client, token: token, pages: opts.ghReleasePages}
+	case b.Provider == providerSource:
+		provider, err := newSourceProvider(b)
+		if err != nil {
+			return err
+		}
+		b.provider = provider
+	case b.Provider == providerOCI:
+		provider, err := newOCIProvider(opts.client, b)
+		if err != nil {
+			return err
+		}
+		b.provider = provider
+	case b.Provider != "":
+		// Other providers are implemented by bine-provider-* plugins.
+		provider, err := newPluginProvider(b.Provider)
+		if err != nil {
+			return err
+		}
+		b.provider = provider
 	default:
 		return fmt.Errorf("unsupported binary provider for %q (%s)", b.Name, b.URL)
 	}
@@ -138,124 +476,245 @@ func (b *bin) checkOutdated(ctx context.Context, resolvedVersion string) (bool,
 		return false, "", fmt.Errorf("binary %q has no resolved version to compare", b.Name)
 	}
 
-	latestVersion, err := b.provider.latestVersion(ctx, b)
+	latestVersion, err := b.provider.latestVersion(withResponseCache(ctx), b)
 	if err != nil {
 		return false, "", fmt.Errorf("check failed for binary %q: %v", b.Name, err)
 	}
 
-	// Compare versions using semver.
-	current := semver.Canonical("v" + strings.TrimPrefix(currentVersion, "v"))
-	latest := semver.Canonical("v" + strings.TrimPrefix(latestVersion, "v"))
-	if latest == "" {
-		return false, "", fmt.Errorf("invalid semver for latest version %q of %s", latest, b.Name)
+	// Compare versions using the version scheme of the binary.
+	scheme := b.versionScheme()
+	if !scheme.valid(latestVersion) {
+		return false, "", fmt.Errorf("invalid %s for latest version %q of %s", scheme.kind(), latestVersion, b.Name)
 	}
 
-	isOutdated := semver.Compare(current, latest) < 0
+	isOutdated := scheme.compare(currentVersion, latestVersion) < 0
 
 	return isOutdated, latestVersion, nil
 }
 
 type binProvider interface {
-	downloadURL(bin *bin) (string, error)
+	downloadURL(ctx context.Context, bin *bin) (string, error)
 	latestVersion(ctx context.Context, bin *bin) (string, error)
 }
 
+// authenticator is implemented by providers that need to authenticate the
+// download of release assets.
+type authenticator interface {
+	authenticate(req *http.Request)
+}
+
+// installer is implemented by providers that can write the binary themselves
+// when they have no download URL to offer. The commit is the revision the
+// binary was built from, if any.
+type installer interface {
+	install(ctx context.Context, bin *bin, binPath string) (commit string, err error)
+}
+
 type goProvider struct {
 	client *http.Client
 }
 
 var _ binProvider = &goProvider{}
 
-func (p *goProvider) downloadURL(b *bin) (string, error) {
+func (p *goProvider) downloadURL(_ context.Context, b *bin) (string, error) {
 	return "", nil // Unused.
 }
 
-// latestVersion retrieves the latest version for a Go package binary.
-//
-// It uses the pkg.go.dev website to find the latest version of the package.
-//
-// TODO: investigate if we can use the Go module proxy instead, e.g. see how
-// github.com/icholy/gomajor does it. But we may also need to extract the path
-// of a module given something like "goa.design/goa/v3/cmd/goa"?
+// latestVersion retrieves the latest version for a Go package binary from the
+// Go module proxy.
 func (p *goProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
-	pkgPath := bin.GoPackage
-	url := fmt.Sprintf("https://pkg.go.dev/%s?tab=versions", pkgPath)
+	return newGoModuleProxy(ctx, p.client).latestVersion(ctx, bin)
+}
+
+type githubProvider struct {
+	client *http.Client
+	token  string
+
+	// apiURL is the URL of the REST API, defaults to githubAPIURL.
+	apiURL string
+
+	// pages is the maximum number of pages of releases read when looking
+	// for the latest version, defaults to defaultReleasePages.
+	pages int
+
+	// releases is filled ahead of latest-version checks, see
+	// Bine.prefetchGitHubReleases.
+	releases *githubReleaseCache
+}
+
+var (
+	_ binProvider   = &githubProvider{}
+	_ authenticator = &githubProvider{}
+)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// downloadURL returns the public download URL of the asset. Assets of private
+// repositories can't be downloaded from there, so when a token is available
+// the asset is downloaded through the API instead. Without an asset pattern,
+// the asset that fits the platform best is selected among the assets of the
+// release.
+func (p *githubProvider) downloadURL(ctx context.Context, b *bin) (string, error) {
+	if p.token == "" && b.Private {
+		return "", fmt.Errorf("private repository %q requires a GitHub API token", b.URL)
+	}
+	publicURL := func() string {
+		return fmt.Sprintf("%s/releases/download/%s/%s", b.URL, b.tag(), b.asset)
+	}
+	if p.token == "" && b.asset != "" {
+		return publicURL(), nil
+	}
+
+	release, err := p.release(ctx, b)
 	if err != nil {
-		return "", fmt.Errorf("create request: %v", err)
+		return "", err
+	}
+
+	if b.asset == "" {
+		platform := b.autoAsset
+		if platform == nil {
+			var n *namer
+			platform, _ = n.assetPlatform(goos + "/" + goarch)
+		}
+		names := make([]string, 0, len(release.Assets))
+		for _, asset := range release.Assets {
+			names = append(names, asset.Name)
+		}
+		if b.asset, err = selectAsset(b, names, platform); err != nil {
+			return "", err
+		}
+	}
+	if p.token == "" {
+		return publicURL(), nil
 	}
 
+	for _, asset := range release.Assets {
+		if asset.Name == b.asset {
+			return asset.URL, nil
+		}
+	}
+
+	return "", fmt.Errorf("asset %q not found in GitHub release %q", b.asset, b.tag())
+}
+
+// release retrieves the release of the tag of the bin.
+func (p *githubProvider) release(ctx context.Context, b *bin) (*githubRelease, error) {
+	owner, repo, err := githubRepo(b)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", p.api(), owner, repo, url.PathEscape(b.tag()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %v", err)
+	}
 	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if p.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.token))
+	}
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("send request: %v", err)
+		return nil, fmt.Errorf("send request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("pkg.go.dev returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("read body: %v", err)
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode GitHub API response: %v", err)
 	}
 
-	// Extract version from HTML using regex to find the first js-versionLink.
-	re := regexp.MustCompile(`<a class="js-versionLink"[^>]*>([^<]+)</a>`)
-	matches := re.FindSubmatch(body)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("extract version: no match found in HTML")
-	}
+	return &release, nil
+}
 
-	latestVersion := string(matches[1])
-	latestVersion = strings.TrimPrefix(latestVersion, "v")
+// authenticate sends the token along with the requests to the API, which is
+// where the assets are downloaded from when a token is available. The
+// octet-stream media type asks the API for the contents of the asset instead
+// of its metadata.
+func (p *githubProvider) authenticate(req *http.Request) {
+	if p.token == "" {
+		return
+	}
+	if api, err := url.Parse(p.api()); err != nil || req.URL.Host != api.Host {
+		return
+	}
 
-	return latestVersion, nil
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.token))
+	if strings.Contains(req.URL.Path, "/releases/assets/") {
+		req.Header.Set("Accept", "application/octet-stream")
+	}
 }
 
-type githubProvider struct {
-	client *http.Client
-	token  string
+// api returns the URL of the REST API.
+func (p *githubProvider) api() string {
+	if p.apiURL == "" {
+		return githubAPIURL
+	}
+	return p.apiURL
 }
 
-var _ binProvider = &githubProvider{}
-
-func (p *githubProvider) downloadURL(b *bin) (string, error) {
-	return fmt.Sprintf("%s/releases/download/%s/%s", b.URL, b.tag(), b.asset), nil
+type githubRelease struct {
+	TagName    string               `json:"tag_name"`
+	Prerelease bool                 `json:"prerelease"`
+	Assets     []githubReleaseAsset `json:"assets,omitempty"`
 }
 
-type githubRelease struct {
-	TagName    string `json:"tag_name"`
-	Prerelease bool   `json:"prerelease"`
+type githubReleaseAsset struct {
+	Name string `json:"name"`
+	// URL of the asset in the API, e.g.
+	// "https://api.github.com/repos/{owner}/{repo}/releases/assets/{id}".
+	URL string `json:"url"`
 }
 
 func (p *githubProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
-	u, err := url.Parse(bin.URL)
+	owner, repo, err := githubRepo(bin)
+	if err != nil {
+		return "", err
+	}
+
+	// Use the releases fetched ahead unless there are more of them to read.
+	if cached, ok := p.releases.get(p.api(), owner, repo); ok {
+		version := latestReleaseVersion(bin, cached.releases)
+		switch {
+		case version != "":
+			return version, nil
+		case cached.complete:
+			return "", fmt.Errorf("no valid %s tags found in GitHub releases matching tag pattern", bin.versionKind())
+		}
+	}
+
+	return ghLatestVersion(ctx, p.client, bin, p.api(), p.token, owner, repo, p.pages)
+}
+
+// githubRepo extracts the owner and the name of the repository from the URL of
+// the bin.
+func githubRepo(b *bin) (string, string, error) {
+	u, err := url.Parse(b.URL)
 	if err != nil {
-		return "", fmt.Errorf("parse URL: %v", err)
+		return "", "", fmt.Errorf("parse URL: %v", err)
 	}
 
-	// Extract owner and repo from the path.
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
 	if len(parts) < 2 {
-		return "", errors.New("could not extract owner/repo")
+		return "", "", errors.New("could not extract owner/repo")
 	}
-	owner, repo := parts[0], parts[1]
 
-	return ghLatestVersion(ctx, p.client, bin, p.token, owner, repo)
+	return parts[0], parts[1], nil
 }
 
 type arigaProvider struct {
 	client *http.Client
 	token  string
+	pages  int
 }
 
 var _ binProvider = &arigaProvider{}
 
-func (p *arigaProvider) downloadURL(b *bin) (string, error) {
+func (p *arigaProvider) downloadURL(_ context.Context, b *bin) (string, error) {
 	parsedURL, err := url.Parse(b.URL)
 	if err != nil {
 		return "", fmt.Errorf("invalid URL %q: %w", b.URL, err)
@@ -267,68 +726,120 @@ func (p *arigaProvider) downloadURL(b *bin) (string, error) {
 }
 
 func (p *arigaProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
-	return ghLatestVersion(ctx, p.client, bin, p.token, "ariga", "atlas")
+	return ghLatestVersion(ctx, p.client, bin, githubAPIURL, p.token, "ariga", "atlas", p.pages)
 }
 
-func ghLatestVersion(ctx context.Context, client *http.Client, bin *bin, token, owner, repo string) (string, error) {
-	// GitHub API endpoint for releases.
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", owner, repo)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("create request: %v", err)
+// defaultReleasePages is the number of pages of releases read by default when
+// looking for the latest version. It can be changed for GitHub only.
+const defaultReleasePages = 3
+
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="?next"?`)
+
+// nextPage returns the URL of the next page of results given in the Link
+// header (RFC 8288), if any. It paginates the APIs of the release providers
+// and the tag lists of OCI registries.
+func nextPage(current, link string) (string, error) {
+	match := nextLinkRegex.FindStringSubmatch(link)
+	if match == nil {
+		return "", nil
 	}
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	if token != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
 	}
-
-	resp, err := client.Do(req)
+	next, err := base.Parse(match[1])
 	if err != nil {
-		return "", fmt.Errorf("send request: %v", err)
+		return "", fmt.Errorf("parse Link header: %v", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
-	}
+	return next.String(), nil
+}
 
-	var releases []githubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return "", fmt.Errorf("failed to decode GitHub API response: %v", err)
+// ghLatestVersion finds the latest version in the releases of a repository.
+// Newer releases are listed first, so pages are read until one of them has a
+// version considered by the bin, up to the given number of pages.
+func ghLatestVersion(ctx context.Context, client *http.Client, bin *bin, apiURL, token, owner, repo string, pages int) (string, error) {
+	if pages < 1 {
+		pages = defaultReleasePages
 	}
 
-	// Find the latest valid semver version among the releases, skipping prereleases.
-	var latestSemver string
-	var latestVersion string
-	for _, release := range releases {
-		if release.Prerelease {
-			continue
+	// GitHub API endpoint for releases.
+	next := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100", apiURL, owner, repo)
+	var releases []githubRelease
+	for page := 0; next != "" && page < pages; page++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return "", fmt.Errorf("create request: %v", err)
+		}
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 		}
 
-		// Extract version from tag using the configured tag pattern.
-		extractedVersion, matched := extractVersionFromTag(bin, release.TagName)
-		if !matched {
-			continue
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("send request: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
 		}
 
-		// Validate that the extracted version is a valid semver.
-		canonicalVersion := semver.Canonical("v" + strings.TrimPrefix(extractedVersion, "v"))
-		if canonicalVersion == "" {
-			continue
+		var batch []githubRelease
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		link := resp.Header.Get("Link")
+		_ = resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to decode GitHub API response: %v", err)
 		}
+		releases = append(releases, batch...)
 
-		if latestSemver == "" || semver.Compare(canonicalVersion, latestSemver) > 0 {
-			latestSemver = canonicalVersion
-			latestVersion = extractedVersion
+		if latestReleaseVersion(bin, releases) != "" {
+			break
+		}
+		next, err = nextPage(next, link)
+		if err != nil {
+			return "", err
 		}
 	}
 
+	latestVersion := latestReleaseVersion(bin, releases)
 	if latestVersion == "" {
-		return "", errors.New("no valid non-prerelease semver tags found in GitHub releases matching tag pattern")
+		return "", fmt.Errorf("no valid %s tags found in GitHub releases matching tag pattern", bin.versionKind())
+	}
+
+	return latestVersion, nil
+}
+
+// latestReleaseVersion returns the latest version among the GitHub releases,
+// skipping prereleases unless the bin follows the prerelease channel.
+func latestReleaseVersion(bin *bin, releases []githubRelease) string {
+	var tags []string
+	for _, release := range releases {
+		if release.Prerelease && !bin.prereleases() {
+			continue
+		}
+		tags = append(tags, release.TagName)
+	}
+
+	return latestTaggedVersion(bin, tags)
+}
+
+// latestTaggedVersion returns the highest version, without the "v" prefix,
+// among the tags matching the tag pattern of the binary. Prereleases are
+// skipped unless the binary follows the prerelease channel, and so are
+// versions out of its range. It returns an empty string if there isn't any.
+func latestTaggedVersion(bin *bin, tags []string) string {
+	var versions []string
+	for _, tag := range tags {
+		// Extract version from tag using the configured tag pattern.
+		if version, matched := extractVersionFromTag(bin, tag); matched {
+			versions = append(versions, version)
+		}
 	}
 
-	return strings.TrimPrefix(latestVersion, "v"), nil
+	return latestVersionOf(bin, versions)
 }
 
 // extractVersionFromTag extracts a version from a tag name using the binary's
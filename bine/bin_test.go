@@ -63,6 +63,17 @@ func TestBinTag(t *testing.T) {
 func TestGitHubProvider(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Log(r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/repos/sevein/perpignan/releases/tags/v1.0.0") {
+			release := githubRelease{
+				TagName: "v1.0.0",
+				Assets: []githubReleaseAsset{
+					{Name: "perpignan-linux-amd64", URL: "https://api.github.com/repos/sevein/perpignan/releases/assets/1234"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(release)
+			return
+		}
 		if strings.Contains(r.URL.Path, "/repos/sevein/perpignan/releases") {
 			releases := []githubRelease{
 				{TagName: "v1.0.2-rc.1", Prerelease: true},
@@ -95,9 +106,74 @@ func TestGitHubProvider(t *testing.T) {
 			asset:   "perpignan-linux-amd64",
 		}
 
-		downloadURL, err := provider.downloadURL(bin)
+		anonymous := &githubProvider{client: client}
+		downloadURL, err := anonymous.downloadURL(t.Context(), bin)
+		assert.NilError(t, err)
+		assert.Equal(t, downloadURL, "https://github.com/sevein/perpignan/releases/download/v1.0.0/perpignan-linux-amd64")
+	})
+
+	t.Run("downloadURL uses the API with a token", func(t *testing.T) {
+		bin := &bin{
+			Name:    "perpignan",
+			Version: "1.0.0",
+			URL:     "https://github.com/sevein/perpignan",
+			asset:   "perpignan-linux-amd64",
+		}
+
+		downloadURL, err := provider.downloadURL(t.Context(), bin)
+		assert.NilError(t, err)
+		assert.Equal(t, downloadURL, "https://api.github.com/repos/sevein/perpignan/releases/assets/1234")
+
+		req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+		assert.NilError(t, err)
+		provider.authenticate(req)
+		assert.Equal(t, req.Header.Get("Authorization"), "Bearer test-token")
+		assert.Equal(t, req.Header.Get("Accept"), "application/octet-stream")
+
+		bin.asset = "perpignan-plan9-amd64"
+		_, err = provider.downloadURL(t.Context(), bin)
+		assert.Error(t, err, `asset "perpignan-plan9-amd64" not found in GitHub release "v1.0.0"`)
+	})
+
+	t.Run("downloadURL selects the asset without asset pattern", func(t *testing.T) {
+		bin := &bin{
+			Name:      "perpignan",
+			Version:   "1.0.0",
+			URL:       "https://github.com/sevein/perpignan",
+			autoAsset: &assetPlatform{goos: "linux", goarch: "amd64"},
+		}
+
+		anonymous := &githubProvider{client: client}
+		downloadURL, err := anonymous.downloadURL(t.Context(), bin)
 		assert.NilError(t, err)
 		assert.Equal(t, downloadURL, "https://github.com/sevein/perpignan/releases/download/v1.0.0/perpignan-linux-amd64")
+		assert.Equal(t, bin.asset, "perpignan-linux-amd64")
+
+		bin.asset = ""
+		bin.autoAsset = &assetPlatform{goos: "linux", goarch: "arm64"}
+		_, err = anonymous.downloadURL(t.Context(), bin)
+		assert.Error(t, err, "no release asset matches linux/arm64, set asset_pattern")
+	})
+
+	t.Run("downloadURL of private repositories requires a token", func(t *testing.T) {
+		bin := &bin{
+			Name:    "perpignan",
+			Version: "1.0.0",
+			URL:     "https://github.com/sevein/perpignan",
+			Private: true,
+			asset:   "perpignan-linux-amd64",
+		}
+
+		anonymous := &githubProvider{client: client}
+		_, err := anonymous.downloadURL(t.Context(), bin)
+		assert.Error(t, err, `private repository "https://github.com/sevein/perpignan" requires a GitHub API token`)
+	})
+
+	t.Run("authenticate skips other hosts", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "https://objects.githubusercontent.com/asset", nil)
+		assert.NilError(t, err)
+		provider.authenticate(req)
+		assert.Equal(t, req.Header.Get("Authorization"), "")
 	})
 
 	t.Run("latestVersion", func(t *testing.T) {
@@ -112,6 +188,33 @@ func TestGitHubProvider(t *testing.T) {
 		assert.NilError(t, err)
 		assert.Equal(t, latestVersion, "1.0.1")
 	})
+
+	t.Run("latestVersion includes prereleases on the prerelease channel", func(t *testing.T) {
+		bin := &bin{
+			Name:    "perpignan",
+			Version: "1.0.0",
+			URL:     "https://github.com/sevein/perpignan",
+			Channel: channelPrerelease,
+		}
+
+		latestVersion, err := provider.latestVersion(t.Context(), bin)
+		assert.NilError(t, err)
+		assert.Equal(t, latestVersion, "1.0.2-rc.1")
+
+		bin.Channel = ""
+		bin.includePrereleases = true
+		latestVersion, err = provider.latestVersion(t.Context(), bin)
+		assert.NilError(t, err)
+		assert.Equal(t, latestVersion, "1.0.2-rc.1")
+	})
+}
+
+func TestLatestTaggedVersion(t *testing.T) {
+	tags := []string{"v1.9.0", "v2.0.0-rc.2", "v2.0.0-rc.10", "v2.0.0-beta.1", "v1.10.0", "nightly"}
+
+	assert.Equal(t, latestTaggedVersion(&bin{Name: "tool"}, tags), "1.10.0")
+	assert.Equal(t, latestTaggedVersion(&bin{Name: "tool", Channel: channelPrerelease}, tags), "2.0.0-rc.10")
+	assert.Equal(t, latestTaggedVersion(&bin{Name: "tool", Channel: channelPrerelease}, append(tags, "v2.0.0")), "2.0.0")
 }
 
 func TestArigaProvider(t *testing.T) {
@@ -151,7 +254,7 @@ func TestArigaProvider(t *testing.T) {
 			asset:   "atlas-linux-amd64",
 		}
 
-		downloadURL, err := provider.downloadURL(bin)
+		downloadURL, err := provider.downloadURL(t.Context(), bin)
 		assert.NilError(t, err)
 		assert.Equal(t, downloadURL, "https://release.ariga.io/atlas/atlas-linux-amd64")
 	})
@@ -219,7 +322,7 @@ func TestBinIsLatest(t *testing.T) {
 		{"LATEST for go package (case-insensitive)", "LATEST", "github.com/foo/bar", true},
 		{"pinned version for go package", "v1.2.3", "github.com/foo/bar", false},
 		{"empty version without go package", "", "", false},
-		{"latest without go package", "latest", "", false},
+		{"latest without go package", "latest", "", true},
 	}
 
 	for _, tt := range tests {
@@ -242,6 +345,7 @@ func TestBinMarkerVersion(t *testing.T) {
 		{"empty version for go package", "", "github.com/foo/bar", "latest"},
 		{"latest for go package", "latest", "github.com/foo/bar", "latest"},
 		{"LATEST for go package (case-insensitive)", "LATEST", "github.com/foo/bar", "latest"},
+		{"latest without go package", "Latest", "", "latest"},
 	}
 
 	for _, tt := range tests {
@@ -352,3 +456,98 @@ func (t *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Use the default transport for the actual request
 	return http.DefaultTransport.RoundTrip(req)
 }
+
+func TestGitHubEnterprise(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/tools/cli/releases" || r.Header.Get("Authorization") != "Bearer ghe-token" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]githubRelease{{TagName: "v2.1.0"}, {TagName: "v2.0.0"}})
+	}))
+	defer server.Close()
+
+	opts := providerOptions{
+		client:       server.Client(),
+		ghAPIToken:   "github-token",
+		ghHostTokens: map[string]string{strings.TrimPrefix(server.URL, "http://"): "ghe-token"},
+	}
+
+	t.Run("Derives the API URL from the host", func(t *testing.T) {
+		b := &bin{Name: "cli", URL: server.URL + "/tools/cli", Provider: "github"}
+		assert.NilError(t, b.loadProvider(opts))
+
+		provider, ok := b.provider.(*githubProvider)
+		assert.Assert(t, ok)
+		assert.Equal(t, provider.apiURL, server.URL+"/api/v3")
+		assert.Equal(t, provider.token, "ghe-token")
+
+		latest, err := b.provider.latestVersion(t.Context(), b)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "2.1.0")
+	})
+
+	t.Run("Uses the configured API URL", func(t *testing.T) {
+		opts := opts
+		opts.ghAPIURL = "https://ghe.example.com/api/v3/"
+
+		b := &bin{Name: "cli", URL: "https://ghe.example.com/tools/cli"}
+		assert.NilError(t, b.loadProvider(opts))
+
+		provider, ok := b.provider.(*githubProvider)
+		assert.Assert(t, ok)
+		assert.Equal(t, provider.apiURL, "https://ghe.example.com/api/v3")
+		assert.Equal(t, provider.token, "", "the github.com token must not be sent to other hosts")
+	})
+
+	t.Run("Keeps github.com on the public API", func(t *testing.T) {
+		opts := opts
+		opts.ghAPIURL = "https://ghe.example.com/api/v3"
+
+		b := &bin{Name: "cli", URL: "https://github.com/tools/cli"}
+		assert.NilError(t, b.loadProvider(opts))
+
+		provider, ok := b.provider.(*githubProvider)
+		assert.Assert(t, ok)
+		assert.Equal(t, provider.apiURL, "https://api.github.com")
+		assert.Equal(t, provider.token, "github-token")
+	})
+}
+
+func TestGitHubReleasePagination(t *testing.T) {
+	var requests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var releases []githubRelease
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=2>; rel="next", <%[1]s%[2]s?per_page=100&page=3>; rel="last"`, server.URL, r.URL.Path))
+			releases = []githubRelease{{TagName: "nightly"}, {TagName: "v2.0.0-rc.1", Prerelease: true}}
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=3>; rel="next"`, server.URL, r.URL.Path))
+			releases = []githubRelease{{TagName: "v1.9.1"}, {TagName: "v1.10.0"}}
+		case "3":
+			releases = []githubRelease{{TagName: "v1.11.0"}}
+		}
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+
+	b := &bin{Name: "tool", URL: "https://github.com/example/tool"}
+
+	t.Run("Follows the Link header until a version is found", func(t *testing.T) {
+		requests = 0
+		latest, err := ghLatestVersion(t.Context(), server.Client(), b, server.URL, "", "example", "tool", 0)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.10.0")
+		assert.Equal(t, requests, 2)
+	})
+
+	t.Run("Stops after the configured number of pages", func(t *testing.T) {
+		requests = 0
+		_, err := ghLatestVersion(t.Context(), server.Client(), b, server.URL, "", "example", "tool", 1)
+		assert.Error(t, err, "no valid non-prerelease semver tags found in GitHub releases matching tag pattern")
+		assert.Equal(t, requests, 1)
+	})
+}
@@ -8,14 +8,16 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/renameio/v2"
 	"github.com/hashicorp/go-retryablehttp"
-	"golang.org/x/mod/semver"
 )
 
 const (
@@ -28,6 +30,9 @@ type Bine struct {
 	client *http.Client
 	config *config
 
+	// ghReleases is shared with the GitHub providers.
+	ghReleases *githubReleaseCache
+
 	Project     string // Project name.
 	CacheDir    string // e.g. ~/.cache/bine/project/linux/amd64/
 	BinDir      string // e.g. ~/.cache/bine/project/linux/amd64/bin/
@@ -60,10 +65,16 @@ func NewWithOptions(opts ...Option) (*Bine, error) {
 type Option func(*options) error
 
 type options struct {
-	ctx          context.Context
-	logger       *logr.Logger
-	cacheDirBase string
-	ghAPIToken   string
+	ctx                context.Context
+	logger             *logr.Logger
+	cacheDirBase       string
+	ghAPIToken         string
+	ghAPIURL           string
+	ghHostTokens       map[string]string
+	glAPIToken         string
+	includePrereleases bool
+	ghReleasePages     int
+	maxAge             time.Duration
 }
 
 // WithContext specifies a custom context for the Bine instance.
@@ -99,6 +110,82 @@ func WithGitHubAPIToken(token string) Option {
 	}
 }
 
+// WithGitHubAPIURL specifies the API URL of a GitHub Enterprise Server
+// instance, e.g. "https://ghe.example.com/api/v3". It's used for repositories
+// not hosted on github.com.
+func WithGitHubAPIURL(apiURL string) Option {
+	return func(o *options) error {
+		o.ghAPIURL = apiURL
+		return nil
+	}
+}
+
+// WithGitHubHostTokens specifies GitHub API tokens for individual hosts as a
+// comma-separated list of "host=token" pairs, e.g. "ghe.example.com=secret".
+func WithGitHubHostTokens(hostTokens string) Option {
+	return func(o *options) error {
+		for pair := range strings.SplitSeq(hostTokens, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			host, token, ok := strings.Cut(pair, "=")
+			if !ok || host == "" || token == "" {
+				// Don't print the pair, it may contain the token.
+				return errors.New("invalid GitHub host tokens, expected comma-separated host=token pairs")
+			}
+			if o.ghHostTokens == nil {
+				o.ghHostTokens = map[string]string{}
+			}
+			o.ghHostTokens[host] = token
+		}
+		return nil
+	}
+}
+
+// WithGitLabAPIToken specifies a GitLab API token for authentication.
+func WithGitLabAPIToken(token string) Option {
+	return func(o *options) error {
+		o.glAPIToken = token
+		return nil
+	}
+}
+
+// WithIncludePrereleases considers the prereleases of every binary when
+// looking for the latest version, as if they followed the prerelease channel.
+func WithIncludePrereleases(include bool) Option {
+	return func(o *options) error {
+		o.includePrereleases = include
+		return nil
+	}
+}
+
+// WithGitHubReleasePages specifies how many pages of GitHub releases, 100
+// releases each, are read at most when looking for the latest version. Zero
+// means the default, three pages.
+func WithGitHubReleasePages(pages int) Option {
+	return func(o *options) error {
+		if pages < 0 {
+			return fmt.Errorf("invalid number of GitHub release pages %d", pages)
+		}
+		o.ghReleasePages = pages
+		return nil
+	}
+}
+
+// WithMaxAge specifies for how long the responses to version lookups are
+// served from the cache without revalidating them. By default, they are
+// always revalidated.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(o *options) error {
+		if maxAge < 0 {
+			return fmt.Errorf("invalid max age %s", maxAge)
+		}
+		o.maxAge = maxAge
+		return nil
+	}
+}
+
 // newBine creates a new Bine instance with the given options.
 func newBine(ctx context.Context, optsConfig *options) (*Bine, error) {
 	if optsConfig == nil {
@@ -107,17 +194,31 @@ func newBine(ctx context.Context, optsConfig *options) (*Bine, error) {
 
 	client := retryablehttp.NewClient()
 	client.RetryMax = 3
+	responses := &responseCache{next: client.HTTPClient.Transport, maxAge: optsConfig.maxAge}
+	client.HTTPClient.Transport = responses
 	stdClient := client.StandardClient()
-
-	config, err := loadConfig(ctx, stdClient, optsConfig.ghAPIToken)
+	ghReleases := newGitHubReleaseCache()
+
+	config, err := loadConfig(ctx, providerOptions{
+		client:       stdClient,
+		ghAPIToken:   optsConfig.ghAPIToken,
+		ghAPIURL:     optsConfig.ghAPIURL,
+		ghHostTokens: optsConfig.ghHostTokens,
+		glAPIToken:   optsConfig.glAPIToken,
+
+		includePrereleases: optsConfig.includePrereleases,
+		ghReleasePages:     optsConfig.ghReleasePages,
+		ghReleases:         ghReleases,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	b := &Bine{
-		client:  stdClient,
-		config:  config,
-		Project: config.Project,
+		client:     stdClient,
+		config:     config,
+		ghReleases: ghReleases,
+		Project:    config.Project,
 	}
 
 	if optsConfig.logger != nil {
@@ -131,11 +232,32 @@ func newBine(ctx context.Context, optsConfig *options) (*Bine, error) {
 		b.CacheDir = cacheDir
 		b.BinDir = filepath.Join(cacheDir, "bin")
 		b.VersionsDir = filepath.Join(cacheDir, "versions")
+		responses.dir = filepath.Join(cacheDir, "http")
+	}
+
+	for _, bin := range config.Bins {
+		if p, ok := bin.provider.(*pluginProvider); ok {
+			p.locate = b.locatePlugin
+		}
 	}
 
 	return b, nil
 }
 
+// locatePlugin returns the path of a provider plugin. Plugins listed as bins
+// in the configuration are installed in the cache, others are looked up in
+// PATH.
+func (b *Bine) locatePlugin(ctx context.Context, name string) (string, error) {
+	if plugin, err := b.load(name); err == nil {
+		if plugin.Provider != "" && pluginPrefix+plugin.Provider == name {
+			return "", fmt.Errorf("%q cannot provide itself", name)
+		}
+		return b.install(ctx, plugin)
+	}
+
+	return exec.LookPath(name)
+}
+
 // cacheDir returns the cache directory for the given project.
 //
 // Only called once at startup.
@@ -196,6 +318,7 @@ func (b *Bine) install(ctx context.Context, bin *bin) (_ string, err error) {
 
 // installVersion installs the configured binary, optionally overriding the
 // version used for the install while preserving the original marker path.
+// Version ranges are resolved unless the version is overridden.
 func (b *Bine) installVersion(ctx context.Context, bin *bin, versionOverride string) (_ string, err error) {
 	defer func() {
 		if err != nil {
@@ -208,48 +331,116 @@ func (b *Bine) installVersion(ctx context.Context, bin *bin, versionOverride str
 		return "", fmt.Errorf("failed to create bin directory: %v", err)
 	}
 
+	if versionOverride == "" && bin.resolvesVersion() {
+		if versionOverride, err = b.resolveVersion(ctx, bin); err != nil {
+			return "", err
+		}
+	}
+
 	installBin := bin
-	var resolvedVersion string
+	var marker versionMarkerDocument
 	if versionOverride != "" {
-		clone := *bin
-		clone.Version = versionOverride
-		installBin = &clone
+		installBin = b.resolvedBin(bin, versionOverride)
 	}
 
 	binPath := filepath.Join(b.BinDir, bin.Name)
 	if installBin.goPkg() {
+		lockedSum, err := b.config.lock.moduleSum(installBin)
+		if err != nil {
+			return "", err
+		}
 		if err := goInstall(ctx, installBin, b.BinDir); err != nil {
 			return "", fmt.Errorf("failed to install Go tool: %v", err)
 		}
+		// Record the module hash so that reinstalls can detect a different
+		// module being served for the same version.
+		info, err := readGoBuildInfo(ctx, binPath)
+		switch {
+		case err != nil && lockedSum != "":
+			_ = os.Remove(binPath)
+			return "", fmt.Errorf("read module sum: %v", err)
+		case err != nil:
+			b.logger.V(1).Info("Could not read build information.", "bin", bin.Name, "err", err)
+		case lockedSum != "" && info.Sum != lockedSum:
+			_ = os.Remove(binPath)
+			return "", fmt.Errorf("module sum mismatch: lock file has %s, installed %s", lockedSum, info.Sum)
+		default:
+			marker.ModuleSum = info.Sum
+		}
+		marker.GoBuild = installBin.goBuildSettings()
 		// For "latest" bins, resolve the actual installed version so we can
 		// detect upgrades in the future.
 		if bin.isLatest() {
 			if versionOverride != "" {
-				resolvedVersion = strings.TrimPrefix(installBin.usableVersion(), "v")
-			} else {
-				if v, err := goInstalledVersion(ctx, binPath); err != nil {
+				marker.ResolvedVersion = strings.TrimPrefix(installBin.usableVersion(), "v")
+			} else if info != nil {
+				if v, err := info.semver(); err != nil {
 					b.logger.V(1).Info("Could not determine installed version for 'latest' tracking.", "bin", bin.Name, "err", err)
 				} else {
-					resolvedVersion = v
+					marker.ResolvedVersion = v
 				}
 			}
 		}
 	} else {
-		if err := binInstall(ctx, b.client, installBin, binPath); err != nil {
+		locked, err := b.config.lock.asset(installBin)
+		if err != nil {
+			return "", err
+		}
+		installed, err := binInstall(ctx, b.client, installBin, binPath, locked)
+		if err != nil {
 			return "", fmt.Errorf("failed to install binary: %v", err)
 		}
+		marker = assetMarker(installBin, installed)
+	}
+	if bin.resolvesVersion() {
+		marker.ResolvedVersion = installBin.unprefixedVersion()
 	}
 
-	if err := b.markVersion(bin, resolvedVersion); err != nil {
+	if err := b.writeVersionMarker(bin, marker); err != nil {
 		return "", err
 	}
 
 	return binPath, nil
 }
 
+// resolvedBin returns a copy of the binary that installs the given version
+// instead of the configured one, with its asset names expanded accordingly.
+func (b *Bine) resolvedBin(configured *bin, version string) *bin {
+	clone := *configured
+	clone.Version = version
+	clone.constraint = nil
+	b.config.namer.run([]*bin{&clone})
+	return &clone
+}
+
+// resolveVersion returns the version installed for a binary with a version
+// range or tracking the latest version: the one recorded in the lock file, or
+// else the one already installed if it's still in range, or else the latest
+// version in range. "latest" bins are not locked.
+func (b *Bine) resolveVersion(ctx context.Context, bin *bin) (string, error) {
+	if bin.isRange() {
+		if entry, err := b.config.lock.entry(bin); err != nil {
+			return "", err
+		} else if entry != nil {
+			return entry.Version, nil
+		}
+	}
+
+	if marker, err := b.readVersionMarker(bin); err == nil && marker.ResolvedVersion != "" && (!bin.isRange() || bin.constraint.check(marker.ResolvedVersion)) {
+		return marker.ResolvedVersion, nil
+	}
+
+	version, err := bin.provider.latestVersion(withResponseCache(ctx), bin)
+	if err != nil {
+		return "", fmt.Errorf("resolve version %q: %v", bin.Version, err)
+	}
+
+	return version, nil
+}
+
 // installed determines if a binary is already installed.
 func (b *Bine) installed(ctx context.Context, bin *bin) (bool, error) {
-	if bin.isLatest() {
+	if bin.isLatest() && bin.goPkg() {
 		_, ok, err := b.latestResolvedVersion(ctx, bin)
 		if err != nil {
 			var resolveErr latestVersionResolutionError
@@ -259,7 +450,15 @@ func (b *Bine) installed(ctx context.Context, bin *bin) (bool, error) {
 			}
 			return false, err
 		}
-		return ok, nil
+		if !ok {
+			return false, nil
+		}
+		// Rebuild when the build settings have changed.
+		marker, err := b.readVersionMarker(bin)
+		if err != nil {
+			return false, nil
+		}
+		return marker.GoBuild.equal(bin.goBuildSettings()), nil
 	}
 
 	binPath := filepath.Join(b.BinDir, bin.Name)
@@ -292,12 +491,44 @@ func (b *Bine) installed(ctx context.Context, bin *bin) (bool, error) {
 		return false, err
 	}
 
-	if sum, err := checksum(binPath); err != nil {
+	sum, err := checksum(binPath)
+	if err != nil {
 		return false, fmt.Errorf("checksum: %v", err)
 	} else if !marker.Checksum.Matches(sum) {
 		return false, nil
 	}
 
+	// Reinstall binaries with a resolved version when the version recorded
+	// in the marker is unknown or differs from the locked one.
+	if bin.resolvesVersion() {
+		if marker.ResolvedVersion == "" || bin.isRange() && !bin.constraint.check(marker.ResolvedVersion) {
+			return false, nil
+		}
+	}
+	if bin.isRange() {
+		if entry, err := b.config.lock.entry(bin); err != nil {
+			return false, nil
+		} else if entry != nil && entry.Version != marker.ResolvedVersion {
+			return false, nil
+		}
+	}
+
+	// Reinstall binaries that don't match the lock file so the install can
+	// verify the download. Stale lock entries are reported by the install.
+	if locked, err := b.config.lock.asset(bin); err != nil {
+		return false, nil
+	} else if locked != nil && (locked.Commit != marker.Commit || locked.BinarySHA256 != "" && locked.BinarySHA256 != sum) {
+		return false, nil
+	}
+	if lockedSum, err := b.config.lock.moduleSum(bin); err != nil {
+		return false, nil
+	} else if lockedSum != "" && lockedSum != marker.ModuleSum {
+		return false, nil
+	}
+	if !marker.GoBuild.equal(bin.goBuildSettings()) {
+		return false, nil
+	}
+
 	return true, nil
 }
 
@@ -310,11 +541,46 @@ func (c versionMarkerChecksum) Matches(sum string) bool {
 	return c.Algorithm == crypto.SHA256.String() && c.Value == sum
 }
 
+// hash returns the hash function named by Algorithm.
+func (c versionMarkerChecksum) hash() (crypto.Hash, bool) {
+	for _, alg := range pinnedChecksumAlgorithms {
+		if c.Algorithm == alg.String() {
+			return alg, true
+		}
+	}
+	return 0, false
+}
+
+// String formats the checksum the way it's pinned in the configuration, e.g.
+// "sha256:<hex>".
+func (c versionMarkerChecksum) String() string {
+	for prefix, alg := range pinnedChecksumAlgorithms {
+		if c.Algorithm == alg.String() {
+			return prefix + ":" + c.Value
+		}
+	}
+	return c.Algorithm + ":" + c.Value
+}
+
 type versionMarkerDocument struct {
 	Checksum versionMarkerChecksum `json:"checksum"`
 	// ResolvedVersion is the actual version installed for "latest" bins.
 	// It is empty for bins with a pinned version.
 	ResolvedVersion string `json:"resolved_version,omitempty"`
+	// Signer identifies the key that signed the installed asset, e.g. the
+	// fingerprint of an OpenPGP key. It is empty if no signature was verified.
+	Signer string `json:"signer,omitempty"`
+	// ArchiveChecksum is the digest of the downloaded asset, computed with the
+	// algorithm of the pinned checksum if there is one.
+	ArchiveChecksum *versionMarkerChecksum `json:"archive_checksum,omitempty"`
+	// ModuleSum is the "h1:" hash of the Go module of go_package bins.
+	ModuleSum string `json:"module_sum,omitempty"`
+	// Commit is the revision that binaries built from source were built from.
+	Commit string `json:"commit,omitempty"`
+	// Asset is the release asset selected for bins without asset pattern.
+	Asset string `json:"asset,omitempty"`
+	// GoBuild holds the build settings of go_package bins, if any.
+	GoBuild *goBuildSettings `json:"go_build,omitempty"`
 }
 
 type latestVersionResolutionError struct {
@@ -389,6 +655,36 @@ func (b *Bine) latestResolvedVersion(ctx context.Context, bin *bin) (string, boo
 // resolvedVersion is the actual semver installed; it is only set for "latest"
 // bins and is used to detect upgrades.
 func (b *Bine) markVersion(bin *bin, resolvedVersion string) error {
+	return b.writeVersionMarker(bin, versionMarkerDocument{ResolvedVersion: resolvedVersion})
+}
+
+// assetMarker returns the version marker details of a binary installed from a
+// release asset.
+func assetMarker(bin *bin, installed *lockedAsset) versionMarkerDocument {
+	doc := versionMarkerDocument{
+		Signer: installed.Signer,
+		Commit: installed.Commit,
+	}
+	if bin.autoAsset != nil {
+		doc.Asset = installed.Asset
+	}
+	if installed.ArchiveSHA256 != "" {
+		doc.ArchiveChecksum = &versionMarkerChecksum{
+			Algorithm: crypto.SHA256.String(),
+			Value:     installed.ArchiveSHA256,
+		}
+	}
+	// binInstall has already verified the pinned checksum.
+	if pinned, err := bin.pinnedChecksum(); err == nil && pinned != nil {
+		doc.ArchiveChecksum = pinned
+	}
+
+	return doc
+}
+
+// writeVersionMarker records the checksum of the installed binary along with
+// the details in doc in the version marker file.
+func (b *Bine) writeVersionMarker(bin *bin, doc versionMarkerDocument) error {
 	versionsDir := filepath.Join(b.VersionsDir, bin.Name)
 	versionMarker := filepath.Join(versionsDir, bin.markerVersion())
 
@@ -403,12 +699,9 @@ func (b *Bine) markVersion(bin *bin, resolvedVersion string) error {
 		return fmt.Errorf("checksum: %v", err)
 	}
 
-	doc := versionMarkerDocument{
-		Checksum: versionMarkerChecksum{
-			Algorithm: crypto.SHA256.String(),
-			Value:     sum,
-		},
-		ResolvedVersion: resolvedVersion,
+	doc.Checksum = versionMarkerChecksum{
+		Algorithm: crypto.SHA256.String(),
+		Value:     sum,
 	}
 	data, err := json.MarshalIndent(doc, "", "\t")
 	if err != nil {
@@ -438,7 +731,7 @@ func (b *Bine) reinstall(ctx context.Context, bin *bin) error {
 
 func (b *Bine) forceReinstall(ctx context.Context, bin *bin) error {
 	versionOverride := ""
-	if bin.isLatest() {
+	if bin.isLatest() && bin.goPkg() {
 		resolvedVersion, ok, err := b.latestResolvedVersion(ctx, bin)
 		if err != nil {
 			var resolveErr latestVersionResolutionError
@@ -538,6 +831,165 @@ func (b *Bine) Reinstall(ctx context.Context) error {
 	return b.SyncForce(ctx)
 }
 
+// ImportGoMod adds go_package bins to the configuration file for the tool
+// directives of a go.mod file, which defaults to the one next to the
+// configuration file. The bins follow the versions required by go.mod unless
+// pin is set, in which case they're pinned to them. Tools that are already
+// configured are skipped. It returns the names of the added bins.
+func (b *Bine) ImportGoMod(path string, pin bool) ([]string, error) {
+	if path == "" {
+		path = goModPath(b.config.path)
+	}
+	f, err := readGoMod(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := goModTools(f, b.config.Bins, pin)
+	if err != nil {
+		return nil, err
+	}
+	// go_mod bins always use the go.mod next to the configuration file.
+	if abs, err := filepath.Abs(path); err != nil {
+		return nil, err
+	} else if !pin && len(tools) > 0 && abs != goModPath(b.config.path) {
+		return nil, fmt.Errorf("%s is not next to the configuration file, use pin to import its versions", path)
+	}
+
+	entries := make([]any, len(tools))
+	for i, tool := range tools {
+		entries[i] = tool
+	}
+	if err := b.config.appendBins(entries); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		bin := &bin{Name: tool.Name, GoPackage: tool.GoPackage, Version: tool.Version, GoMod: tool.GoMod}
+		if bin.GoMod {
+			_, version, _ := goModRequirement(f, bin.GoPackage)
+			bin.Version = strings.TrimPrefix(version, "v")
+		}
+		if err := bin.loadProvider(providerOptions{client: b.client}); err != nil {
+			return nil, err
+		}
+		b.config.Bins = append(b.config.Bins, bin)
+		names = append(names, bin.Name)
+	}
+
+	return names, nil
+}
+
+// Lock installs all binaries defined in the configuration and records the
+// downloaded artifacts in the lock file, creating it if needed.
+func (b *Bine) Lock(ctx context.Context) error {
+	if b.config.lock == nil {
+		b.config.lock = newLockFile(lockFilePath(b.config.path))
+	}
+	b.config.lock.prune(b.config.Bins)
+
+	return b.lockBins(ctx, b.config.Bins)
+}
+
+// lockBins reinstalls the given binaries without checking the current lock
+// entries and records the results in the lock file. Version ranges are
+// resolved to the latest version in range.
+func (b *Bine) lockBins(ctx context.Context, bins []*bin) error {
+	for _, bin := range bins {
+		if bin.isLatest() {
+			continue // "latest" bins can't be locked at all.
+		}
+
+		target, constraint := bin, ""
+		if bin.isRange() {
+			version, err := bin.provider.latestVersion(withResponseCache(ctx), bin)
+			if err != nil {
+				return fmt.Errorf("lock: %q: resolve version range %q: %v", bin.Name, bin.Version, err)
+			}
+			target, constraint = b.resolvedBin(bin, version), bin.Version
+		}
+
+		// Go packages are built locally so their binaries are not
+		// reproducible byte for byte, but the module hash is.
+		if bin.goPkg() {
+			sum, err := b.lockGoBin(ctx, bin, target)
+			if err != nil {
+				return fmt.Errorf("lock: %q: %v", bin.Name, err)
+			}
+			b.config.lock.setModuleSum(target, sum)
+		} else {
+			locked, err := b.lockBin(ctx, bin, target)
+			if err != nil {
+				return fmt.Errorf("lock: %q: %v", bin.Name, err)
+			}
+			b.config.lock.set(target, locked)
+		}
+		b.config.lock.setConstraint(target, constraint)
+	}
+
+	if err := b.config.lock.write(); err != nil {
+		return fmt.Errorf("lock: write lock file: %v", err)
+	}
+
+	return nil
+}
+
+// lockBin installs the target version of a binary, which differs from the
+// configured one for version ranges, and returns the installed asset.
+func (b *Bine) lockBin(ctx context.Context, bin, target *bin) (*lockedAsset, error) {
+	if err := os.MkdirAll(b.BinDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create bin directory: %v", err)
+	}
+
+	binPath := filepath.Join(b.BinDir, bin.Name)
+	locked, err := binInstall(ctx, b.client, target, binPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install binary: %v", err)
+	}
+
+	marker := assetMarker(target, locked)
+	if bin.isRange() {
+		marker.ResolvedVersion = target.unprefixedVersion()
+	}
+	if err := b.writeVersionMarker(bin, marker); err != nil {
+		return nil, err
+	}
+
+	return locked, nil
+}
+
+// lockGoBin reinstalls the target version of a go_package binary and returns
+// its module hash.
+func (b *Bine) lockGoBin(ctx context.Context, bin, target *bin) (string, error) {
+	if err := os.MkdirAll(b.BinDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create bin directory: %v", err)
+	}
+
+	if err := goInstall(ctx, target, b.BinDir); err != nil {
+		return "", fmt.Errorf("failed to install Go tool: %v", err)
+	}
+
+	binPath := filepath.Join(b.BinDir, bin.Name)
+	info, err := readGoBuildInfo(ctx, binPath)
+	if err != nil {
+		return "", fmt.Errorf("read module sum: %v", err)
+	}
+	if info.Sum == "" {
+		return "", fmt.Errorf("no module sum found in %q", binPath)
+	}
+
+	marker := versionMarkerDocument{ModuleSum: info.Sum, GoBuild: bin.goBuildSettings()}
+	if bin.isRange() {
+		marker.ResolvedVersion = target.unprefixedVersion()
+	}
+	if err := b.writeVersionMarker(bin, marker); err != nil {
+		return "", err
+	}
+
+	return info.Sum, nil
+}
+
 func (b *Bine) Upgrade(ctx context.Context) ([]*ListItem, error) {
 	return b.upgradeBins(ctx, b.config.Bins)
 }
@@ -563,10 +1015,16 @@ func (b *Bine) ListOne(ctx context.Context, name string, installedOnly, outdated
 }
 
 func (b *Bine) upgradeBins(ctx context.Context, bins []*bin) ([]*ListItem, error) {
-	updates, err := b.listBins(ctx, bins, false, true)
+	// go_mod bins are upgraded by editing go.mod.
+	upgradable := slices.DeleteFunc(slices.Clone(bins), func(bin *bin) bool { return bin.GoMod })
+	updates, err := b.listBins(ctx, upgradable, false, true)
 	if err != nil {
 		return nil, err
 	}
+	// Leave out the bins only listed for a newer version out of their range.
+	updates = slices.DeleteFunc(updates, func(item *ListItem) bool {
+		return item.Latest == "" && item.OutdatedCheckError == ""
+	})
 
 	// Halt if any binary has an outdated check error.
 	for _, item := range updates {
@@ -576,22 +1034,50 @@ func (b *Bine) upgradeBins(ctx context.Context, bins []*bin) ([]*ListItem, error
 	}
 
 	if len(updates) > 0 {
-		if err := b.config.update(updates); err != nil {
+		changes := b.config.changes(updates)
+		if err := b.pinChecksums(ctx, changes); err != nil {
+			return nil, err
+		}
+		if err := b.config.apply(changes); err != nil {
 			return nil, err
 		}
 
-		// For "latest" bins, config.update() does not modify the version in the
-		// config file. Reinstall them explicitly so Sync() does not depend on
-		// marker deletion as an implicit signal.
+		// For "latest" bins and version ranges, config.update() does not
+		// modify the version in the config file. Reinstall them explicitly so
+		// Sync() does not depend on marker deletion as an implicit signal.
+		// Version ranges are reinstalled by lockBins when there is a lock file.
 		for _, item := range updates {
 			for _, bin := range b.config.Bins {
-				if bin.Name == item.Name && bin.isLatest() {
+				if bin.Name != item.Name {
+					continue
+				}
+				switch {
+				case bin.isLatest():
 					if err := b.reinstall(ctx, bin); err != nil {
 						return updates, fmt.Errorf("reinstall latest-tracking bin %q: %v", bin.Name, err)
 					}
+				case bin.isRange() && b.config.lock == nil:
+					if _, err := b.installVersion(ctx, bin, strings.TrimPrefix(item.Latest, "v")); err != nil {
+						return updates, fmt.Errorf("reinstall bin %q with version range: %v", bin.Name, err)
+					}
 				}
 			}
 		}
+
+		// Keep the lock file in sync with the new versions.
+		if b.config.lock != nil {
+			var upgraded []*bin
+			for _, item := range updates {
+				for _, bin := range b.config.Bins {
+					if bin.Name == item.Name {
+						upgraded = append(upgraded, bin)
+					}
+				}
+			}
+			if err := b.lockBins(ctx, upgraded); err != nil {
+				return updates, err
+			}
+		}
 	}
 
 	if err := b.syncBins(ctx, bins, false); err != nil {
@@ -601,13 +1087,40 @@ func (b *Bine) upgradeBins(ctx context.Context, bins []*bin) ([]*ListItem, error
 	return updates, nil
 }
 
+// pinChecksums computes the checksums pinned in the configuration for the new
+// versions of the upgraded binaries.
+func (b *Bine) pinChecksums(ctx context.Context, changes map[string]*binUpdate) error {
+	for _, bin := range b.config.Bins {
+		change, ok := changes[bin.Name]
+		if !ok || change.version == "" || len(bin.Checksums) == 0 {
+			continue
+		}
+
+		next := *bin
+		next.Version = change.version
+		checksums, err := pinChecksums(ctx, b.client, b.config.namer, &next)
+		if err != nil {
+			return fmt.Errorf("upgrade %q: %v", bin.Name, err)
+		}
+		change.checksums = checksums
+	}
+
+	return nil
+}
+
 type ListItem struct {
 	Name string `json:"name"`
 	// Prefixed with "v" if it's a semver.
 	Version string `json:"version"`
 	// Prefixed with "v" if it's a semver.
-	Latest             string `json:"latest,omitempty"`
+	Latest string `json:"latest,omitempty"`
+	// LatestOverall is the latest version regardless of the version range,
+	// for bins with a version range when it differs from Latest. Prefixed
+	// with "v" if it's a semver.
+	LatestOverall      string `json:"latest_overall,omitempty"`
 	OutdatedCheckError string `json:"outdated_check_error,omitempty"`
+	// Signer identifies the key that signed the installed binary, if any.
+	Signer string `json:"signer,omitempty"`
 }
 
 func (b *Bine) List(ctx context.Context, installedOnly, outdatedOnly bool) ([]*ListItem, error) {
@@ -617,6 +1130,11 @@ func (b *Bine) List(ctx context.Context, installedOnly, outdatedOnly bool) ([]*L
 func (b *Bine) listBins(ctx context.Context, bins []*bin, installedOnly, outdatedOnly bool) ([]*ListItem, error) {
 	var items []*ListItem
 
+	if outdatedOnly {
+		b.prefetchGitHubReleases(ctx, bins)
+		defer b.ghReleases.reset()
+	}
+
 	for _, bin := range bins {
 		if installedOnly {
 			ok, err := b.installed(ctx, bin)
@@ -633,14 +1151,19 @@ func (b *Bine) listBins(ctx context.Context, bins []*bin, installedOnly, outdate
 		resolvedVersion := ""
 		latestInstalled := false
 		var latestResolvedError error
-		if bin.isLatest() {
+		if bin.isLatest() && bin.goPkg() {
 			resolvedVersion, latestInstalled, latestResolvedError = b.latestResolvedVersion(ctx, bin)
+		} else if bin.resolvesVersion() {
+			// Resolved versions are compared with the version installed.
+			if marker, err := b.readVersionMarker(bin); err == nil && marker.ResolvedVersion != "" {
+				resolvedVersion, latestInstalled = marker.ResolvedVersion, true
+			}
 		}
 
-		var latestVersion string
+		var latestVersion, latestOverall string
 		var outdatedCheckError string
 		if outdatedOnly {
-			if bin.isLatest() {
+			if bin.isLatest() || bin.isRange() {
 				if !latestInstalled {
 					continue
 				}
@@ -656,33 +1179,69 @@ func (b *Bine) listBins(ctx context.Context, bins []*bin, installedOnly, outdate
 			}
 			if err != nil {
 				outdatedCheckError = err.Error()
-			} else if outdatedCheckError == "" && !outdated {
-				continue
 			}
-		}
 
-		// Append the latest version with "v" prefix if it's a semver.
-		if ver := semver.Canonical("v" + strings.TrimPrefix(latestVersion, "v")); ver != "" {
-			latestVersion = "v" + latestVersion
+			if bin.isRange() && outdatedCheckError == "" {
+				latestOverall = b.latestOverallVersion(ctx, bin, latestVersion)
+			}
+
+			// Bins up to date within their range are still listed when a
+			// newer version is out of range, without a version to upgrade to.
+			if outdatedCheckError == "" && !outdated {
+				if latestOverall == "" || latestOverall == resolvedVersion {
+					continue
+				}
+				latestVersion = ""
+			}
 		}
 
-		// Display version: for "latest" bins show the resolved version if known.
+		// Append the latest versions with "v" prefix if they're semver.
+		latestVersion = bin.displayVersion(latestVersion)
+		latestOverall = bin.displayVersion(latestOverall)
+
+		// Display version: for "latest" bins and version ranges show the
+		// resolved version if known.
 		version := bin.usableVersion()
-		if bin.isLatest() && resolvedVersion != "" {
-			version = "v" + resolvedVersion
+		if (bin.isLatest() || bin.isRange()) && resolvedVersion != "" {
+			version = bin.displayVersion(resolvedVersion)
+		}
+
+		var signer string
+		if marker, err := b.readVersionMarker(bin); err == nil {
+			signer = marker.Signer
 		}
 
 		items = append(items, &ListItem{
 			Name:               bin.Name,
 			Version:            version,
 			Latest:             latestVersion,
+			LatestOverall:      latestOverall,
 			OutdatedCheckError: outdatedCheckError,
+			Signer:             signer,
 		})
 	}
 
 	return items, nil
 }
 
+// latestOverallVersion returns the latest version of a binary with a version
+// range regardless of the range, or an empty string if it's the latest version
+// in range or if it can't be found.
+func (b *Bine) latestOverallVersion(ctx context.Context, bin *bin, latestInRange string) string {
+	unconstrained := *bin
+	unconstrained.constraint = nil
+	version, err := unconstrained.provider.latestVersion(withResponseCache(ctx), &unconstrained)
+	if err != nil {
+		b.logger.V(1).Info("Could not find the latest version out of range.", "bin", bin.Name, "err", err)
+		return ""
+	}
+	if version == latestInRange {
+		return ""
+	}
+
+	return version
+}
+
 // clientLogger is a custom logger for the retryablehttp client.
 type clientLogger struct {
 	logger logr.Logger
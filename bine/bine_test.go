@@ -126,3 +126,46 @@ func TestSyncForceReinstallsExistingBinaries(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, string(blob), "binary-3")
 }
+
+func TestGetReinstallsWhenGoBuildSettingsChange(t *testing.T) {
+	injectFakeExec(t, "TestHelperProcessWithCounter")
+	t.Setenv("BINE_HELPER_COUNTER", filepath.Join(t.TempDir(), "counter"))
+
+	b, tool := newForceTestBine(t)
+	get := func(expected string) {
+		t.Helper()
+		path, err := b.Get(t.Context(), tool.Name)
+		assert.NilError(t, err)
+		blob, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), expected)
+	}
+
+	get("binary-1")
+	get("binary-1")
+
+	tool.GoTags = []string{"sqlite3"}
+	get("binary-2")
+	get("binary-2")
+
+	marker, err := b.readVersionMarker(tool)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, marker.GoBuild, &goBuildSettings{Tags: []string{"sqlite3"}})
+
+	tool.GoTags = nil
+	get("binary-3")
+}
+
+func TestWithGitHubHostTokens(t *testing.T) {
+	t.Parallel()
+
+	var opts options
+	assert.NilError(t, WithGitHubHostTokens("ghe.example.com=one, github.com=two,")(&opts))
+	assert.DeepEqual(t, opts.ghHostTokens, map[string]string{
+		"ghe.example.com": "one",
+		"github.com":      "two",
+	})
+
+	err := WithGitHubHostTokens("secret")(&opts)
+	assert.Error(t, err, "invalid GitHub host tokens, expected comma-separated host=token pairs")
+}
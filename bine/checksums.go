@@ -0,0 +1,332 @@
+package bine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	_ "crypto/sha256" // Register SHA-256.
+	_ "crypto/sha512" // Register SHA-512.
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxSidecarSize limits the size of the small files downloaded alongside an
+// asset, e.g. checksum files or signatures.
+const maxSidecarSize = 10 << 20
+
+// fetchSidecar downloads a small file published alongside a release asset.
+func fetchSidecar(ctx context.Context, client *http.Client, b *bin, url string) ([]byte, error) {
+	req, err := newDownloadRequest(ctx, b, url)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %q: %v", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: status %s (%s)", resp.Status, url)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxSidecarSize))
+}
+
+// sidecarURL returns the download URL of another asset of the same release.
+func sidecarURL(ctx context.Context, b *bin, asset string) (string, error) {
+	sidecar := *b
+	sidecar.asset = asset
+	return b.provider.downloadURL(ctx, &sidecar)
+}
+
+// fetchChecksumFile downloads the checksum file published with the asset.
+func fetchChecksumFile(ctx context.Context, client *http.Client, b *bin) ([]byte, error) {
+	url, err := sidecarURL(ctx, b, b.checksumAsset)
+	if err != nil {
+		return nil, fmt.Errorf("generate checksum file URL: %v", err)
+	}
+
+	blob, err := fetchSidecar(ctx, client, b, url)
+	if err != nil {
+		return nil, fmt.Errorf("checksum file: %v", err)
+	}
+
+	return blob, nil
+}
+
+// verifyChecksums checks that the archive at archivePath matches the digest
+// listed for the asset in the checksum file.
+func verifyChecksums(b *bin, checksums []byte, archivePath string) error {
+	expected, err := parseChecksumFile(checksums, b.asset)
+	if err != nil {
+		return fmt.Errorf("checksum file %q: %v", b.checksumAsset, err)
+	}
+
+	alg, err := digestAlgorithm(expected)
+	if err != nil {
+		return fmt.Errorf("checksum file %q: %v", b.checksumAsset, err)
+	}
+
+	actual, err := fileDigest(archivePath, alg)
+	if err != nil {
+		return fmt.Errorf("checksum: %v", err)
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch for %q: %s lists %s, downloaded %s", b.asset, b.checksumAsset, expected, actual)
+	}
+
+	return nil
+}
+
+// parseChecksumFile returns the digest listed for asset. It understands the
+// output of sha256sum and friends ("<digest>  <name>" or "<digest> *<name>"),
+// the BSD format ("SHA256 (<name>) = <digest>") and files that only contain a
+// digest.
+func parseChecksumFile(data []byte, asset string) (string, error) {
+	var (
+		digests []string
+		found   string
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var digest, name string
+		if before, after, ok := strings.Cut(line, ") = "); ok {
+			if _, n, ok := strings.Cut(before, " ("); ok {
+				digest, name = after, n
+			}
+		} else {
+			fields := strings.Fields(line)
+			digest = fields[0]
+			if len(fields) > 1 {
+				name = strings.TrimPrefix(fields[len(fields)-1], "*")
+			}
+		}
+
+		digests = append(digests, digest)
+		if name != "" && (name == asset || path.Base(name) == asset) {
+			found = digest
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	switch {
+	case found != "":
+		return found, nil
+	case len(digests) == 1 && !strings.ContainsAny(strings.TrimSpace(string(data)), " \t"):
+		return digests[0], nil
+	default:
+		return "", fmt.Errorf("no checksum found for %q", asset)
+	}
+}
+
+// digestAlgorithm guesses the hash function of a hex-encoded digest from its
+// length.
+func digestAlgorithm(digest string) (crypto.Hash, error) {
+	if _, err := hex.DecodeString(digest); err != nil {
+		return 0, fmt.Errorf("invalid digest %q", digest)
+	}
+
+	switch len(digest) {
+	case crypto.SHA256.Size() * 2:
+		return crypto.SHA256, nil
+	case crypto.SHA512.Size() * 2:
+		return crypto.SHA512, nil
+	default:
+		return 0, errors.New("unsupported digest length")
+	}
+}
+
+// fileDigest computes the hex-encoded digest of the file at filePath.
+func fileDigest(filePath string, alg crypto.Hash) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := alg.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// pinnedChecksumAlgorithms maps the prefixes accepted in the checksums field of
+// the configuration to their hash functions.
+var pinnedChecksumAlgorithms = map[string]crypto.Hash{
+	"sha256": crypto.SHA256,
+	"sha512": crypto.SHA512,
+}
+
+// parsePinnedChecksum parses a digest pinned in the configuration, e.g.
+// "sha256:<hex>".
+func parsePinnedChecksum(s string) (versionMarkerChecksum, error) {
+	prefix, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return versionMarkerChecksum{}, fmt.Errorf("invalid checksum %q: missing algorithm prefix", s)
+	}
+	alg, ok := pinnedChecksumAlgorithms[strings.ToLower(prefix)]
+	if !ok {
+		return versionMarkerChecksum{}, fmt.Errorf("invalid checksum %q: unsupported algorithm %q", s, prefix)
+	}
+	if blob, err := hex.DecodeString(value); err != nil || len(blob) != alg.Size() {
+		return versionMarkerChecksum{}, fmt.Errorf("invalid checksum %q: expected %d hex-encoded bytes", s, alg.Size())
+	}
+
+	return versionMarkerChecksum{Algorithm: alg.String(), Value: strings.ToLower(value)}, nil
+}
+
+// pinnedChecksum returns the digest of the asset pinned in the configuration
+// for the current platform, or nil if there isn't one.
+func (b bin) pinnedChecksum() (*versionMarkerChecksum, error) {
+	s, ok := b.Checksums[platform()]
+	if !ok {
+		return nil, nil
+	}
+
+	c, err := parsePinnedChecksum(s)
+	if err != nil {
+		return nil, fmt.Errorf("checksums[%q]: %v", platform(), err)
+	}
+
+	return &c, nil
+}
+
+// verifyPinnedChecksum checks that the archive at archivePath matches the
+// digest pinned in the configuration.
+func verifyPinnedChecksum(pinned versionMarkerChecksum, archivePath string) error {
+	alg, ok := pinned.hash()
+	if !ok {
+		return fmt.Errorf("unsupported checksum algorithm %q", pinned.Algorithm)
+	}
+
+	actual, err := fileDigest(archivePath, alg)
+	if err != nil {
+		return fmt.Errorf("checksum: %v", err)
+	}
+	if actual != pinned.Value {
+		downloaded := versionMarkerChecksum{Algorithm: pinned.Algorithm, Value: actual}
+		return fmt.Errorf("archive checksum mismatch: configuration pins %s, downloaded %s", pinned, downloaded)
+	}
+
+	return nil
+}
+
+// pinChecksums computes the digests of the assets of the bin for every
+// platform listed in its checksums field, e.g. after changing its version.
+// The algorithm of each entry is preserved. Binaries written by the provider
+// itself can only be pinned for the current platform.
+func pinChecksums(ctx context.Context, client *http.Client, n *namer, b *bin) (map[string]string, error) {
+	current := platform()
+	checksums := make(map[string]string, len(b.Checksums))
+	for _, platform := range slices.Sorted(maps.Keys(b.Checksums)) {
+		pinned := b.Checksums[platform]
+		c, err := parsePinnedChecksum(pinned)
+		if err != nil {
+			return nil, fmt.Errorf("checksums[%q]: %v", platform, err)
+		}
+		alg, _ := c.hash()
+
+		target := *b
+		target.asset, err = n.expandPlatform(b, b.assetPattern(), platform)
+		if err != nil {
+			return nil, fmt.Errorf("checksums[%q]: %v", platform, err)
+		}
+		if target.asset == "" {
+			if target.autoAsset, err = n.assetPlatform(platform); err != nil {
+				return nil, fmt.Errorf("checksums[%q]: %v", platform, err)
+			}
+		}
+		if b.DownloadURL != "" {
+			target.expandedDownloadURL, err = n.expandPlatform(b, b.DownloadURL, platform)
+			if err != nil {
+				return nil, fmt.Errorf("checksums[%q]: %v", platform, err)
+			}
+		}
+		url, err := b.provider.downloadURL(ctx, &target)
+		if err != nil {
+			return nil, fmt.Errorf("generate download URL: %v", err)
+		}
+
+		var digest string
+		if inst, ok := b.provider.(installer); ok && url == "" {
+			if platform != current {
+				return nil, fmt.Errorf("checksums[%q]: the binary is installed by the provider, it can only be pinned for %q", platform, current)
+			}
+			digest, err = installDigest(ctx, inst, &target, alg)
+		} else {
+			digest, err = downloadDigest(ctx, client, b, url, alg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("checksums[%q]: %v", platform, err)
+		}
+
+		prefix, _, _ := strings.Cut(pinned, ":")
+		checksums[platform] = prefix + ":" + digest
+	}
+
+	return checksums, nil
+}
+
+// installDigest computes the hex-encoded digest of the binary written by the
+// provider, installed in a temporary directory.
+func installDigest(ctx context.Context, inst installer, b *bin, alg crypto.Hash) (string, error) {
+	dir, err := os.MkdirTemp("", "bine-pin-*")
+	if err != nil {
+		return "", fmt.Errorf("create temporary directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	binPath := filepath.Join(dir, b.Name)
+	if _, err := inst.install(ctx, b, binPath); err != nil {
+		return "", fmt.Errorf("provider install failed: %v", err)
+	}
+
+	return fileDigest(binPath, alg)
+}
+
+// downloadDigest computes the hex-encoded digest of the file at url without
+// storing it.
+func downloadDigest(ctx context.Context, client *http.Client, b *bin, url string, alg crypto.Hash) (string, error) {
+	req, err := newDownloadRequest(ctx, b, url)
+	if err != nil {
+		return "", fmt.Errorf("create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download %q: %v", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed: status %s (%s)", resp.Status, url)
+	}
+
+	h := alg.New()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", fmt.Errorf("download %q: %v", url, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,283 @@
+package bine
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseChecksumFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		asset   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "sha256sum output",
+			data:  "aaa  tool_linux_amd64.tar.gz\nbbb  tool_darwin_arm64.tar.gz\n",
+			asset: "tool_darwin_arm64.tar.gz",
+			want:  "bbb",
+		},
+		{
+			name:  "binary mode marker",
+			data:  "aaa *tool_linux_amd64.tar.gz\n",
+			asset: "tool_linux_amd64.tar.gz",
+			want:  "aaa",
+		},
+		{
+			name:  "relative paths",
+			data:  "aaa  ./dist/tool_linux_amd64.tar.gz\n",
+			asset: "tool_linux_amd64.tar.gz",
+			want:  "aaa",
+		},
+		{
+			name:  "BSD format",
+			data:  "SHA256 (tool_linux_amd64.tar.gz) = aaa\n",
+			asset: "tool_linux_amd64.tar.gz",
+			want:  "aaa",
+		},
+		{
+			name:  "single digest",
+			data:  "aaa\n",
+			asset: "tool_linux_amd64.tar.gz",
+			want:  "aaa",
+		},
+		{
+			name:    "missing asset",
+			data:    "aaa  tool_linux_amd64.tar.gz\n",
+			asset:   "tool_darwin_arm64.tar.gz",
+			wantErr: `no checksum found for "tool_darwin_arm64.tar.gz"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChecksumFile([]byte(tt.data), tt.asset)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestFileDigest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	assert.NilError(t, os.WriteFile(path, []byte("binary"), 0o644))
+
+	sum, err := fileDigest(path, crypto.SHA256)
+	assert.NilError(t, err)
+	assert.Equal(t, sum, sha256Hex("binary"))
+
+	alg, err := digestAlgorithm(sum)
+	assert.NilError(t, err)
+	assert.Equal(t, alg, crypto.SHA256)
+
+	sum512 := sha512.Sum512([]byte("binary"))
+	alg, err = digestAlgorithm(hex.EncodeToString(sum512[:]))
+	assert.NilError(t, err)
+	assert.Equal(t, alg, crypto.SHA512)
+
+	_, err = digestAlgorithm("abc")
+	assert.ErrorContains(t, err, "invalid digest")
+}
+
+func TestBinInstallVerifiesChecksumFile(t *testing.T) {
+	t.Run("Installs when the checksum matches", func(t *testing.T) {
+		b, tool := newLockTestBine(t, map[string]string{
+			"/tool_1.0.0":    "binary",
+			"/checksums.txt": fmt.Sprintf("%s  tool_1.0.0\n", sha256Hex("binary")),
+		})
+		tool.checksumAsset = "checksums.txt"
+
+		_, err := b.Get(t.Context(), tool.Name)
+		assert.NilError(t, err)
+	})
+
+	t.Run("Fails when the checksum differs", func(t *testing.T) {
+		b, tool := newLockTestBine(t, map[string]string{
+			"/tool_1.0.0":    "binary",
+			"/checksums.txt": fmt.Sprintf("%s  tool_1.0.0\n", sha256Hex("other")),
+		})
+		tool.checksumAsset = "checksums.txt"
+
+		_, err := b.Get(t.Context(), tool.Name)
+		assert.ErrorContains(t, err, `checksum mismatch for "tool_1.0.0": checksums.txt lists`)
+
+		_, err = os.Stat(filepath.Join(b.BinDir, tool.Name))
+		assert.Assert(t, os.IsNotExist(err))
+	})
+
+	t.Run("Fails when the checksum file is missing", func(t *testing.T) {
+		b, tool := newLockTestBine(t, map[string]string{
+			"/tool_1.0.0": "binary",
+		})
+		tool.checksumAsset = "checksums.txt"
+
+		_, err := b.Get(t.Context(), tool.Name)
+		assert.ErrorContains(t, err, "checksum file: download failed: status 404")
+	})
+}
+
+func TestParsePinnedChecksum(t *testing.T) {
+	sha512Sum := sha512.Sum512([]byte("binary"))
+
+	tests := []struct {
+		name    string
+		value   string
+		want    versionMarkerChecksum
+		wantErr string
+	}{
+		{
+			name:  "Parses SHA-256 digests",
+			value: "sha256:" + sha256Hex("binary"),
+			want:  versionMarkerChecksum{Algorithm: "SHA-256", Value: sha256Hex("binary")},
+		},
+		{
+			name:  "Parses SHA-512 digests",
+			value: "SHA512:" + hex.EncodeToString(sha512Sum[:]),
+			want:  versionMarkerChecksum{Algorithm: "SHA-512", Value: hex.EncodeToString(sha512Sum[:])},
+		},
+		{
+			name:    "Requires an algorithm",
+			value:   sha256Hex("binary"),
+			wantErr: "missing algorithm prefix",
+		},
+		{
+			name:    "Rejects unknown algorithms",
+			value:   "md5:" + sha256Hex("binary"),
+			wantErr: `unsupported algorithm "md5"`,
+		},
+		{
+			name:    "Rejects digests of the wrong size",
+			value:   "sha512:" + sha256Hex("binary"),
+			wantErr: "expected 64 hex-encoded bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePinnedChecksum(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestBinInstallVerifiesPinnedChecksum(t *testing.T) {
+	modifyRuntime(t, "linux", "amd64")
+
+	t.Run("Installs and records the pinned checksum", func(t *testing.T) {
+		sum := sha512.Sum512([]byte("binary"))
+		pinned := "sha512:" + hex.EncodeToString(sum[:])
+
+		b, tool := newLockTestBine(t, map[string]string{"/tool_1.0.0": "binary"})
+		tool.Checksums = map[string]string{"linux/amd64": pinned, "darwin/arm64": "sha256:" + sha256Hex("other")}
+
+		_, err := b.Get(t.Context(), tool.Name)
+		assert.NilError(t, err)
+
+		marker, err := b.readVersionMarker(tool)
+		assert.NilError(t, err)
+		assert.Equal(t, marker.ArchiveChecksum.String(), pinned)
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.Assert(t, items[0].OK)
+	})
+
+	t.Run("Fails when the download differs", func(t *testing.T) {
+		b, tool := newLockTestBine(t, map[string]string{"/tool_1.0.0": "binary"})
+		tool.Checksums = map[string]string{"linux/amd64": "sha256:" + sha256Hex("other")}
+
+		_, err := b.Get(t.Context(), tool.Name)
+		assert.ErrorContains(t, err, fmt.Sprintf("archive checksum mismatch: configuration pins sha256:%s, downloaded sha256:%s", sha256Hex("other"), sha256Hex("binary")))
+
+		_, err = os.Stat(filepath.Join(b.BinDir, tool.Name))
+		assert.Assert(t, os.IsNotExist(err))
+	})
+}
+
+func TestPinChecksums(t *testing.T) {
+	modifyRuntime(t, "linux", "amd64")
+
+	b, tool := newLockTestBine(t, map[string]string{
+		"/tool_1.1.0_linux_amd64":  "linux-1.1.0",
+		"/tool_1.1.0_Darwin_arm64": "darwin-1.1.0",
+	})
+	tool.AssetPattern = "{name}_{version}_{goos}_{goarch}"
+	tool.Modifiers = map[string]map[string]string{"goos": {"darwin": "Darwin"}}
+	tool.Checksums = map[string]string{
+		"linux/amd64":  "sha256:" + sha256Hex("linux-1.0.0"),
+		"darwin/arm64": "SHA256:" + sha256Hex("darwin-1.0.0"),
+	}
+	b.config.namer = &namer{}
+
+	changes := map[string]*binUpdate{tool.Name: {version: "1.1.0"}}
+	err := b.pinChecksums(t.Context(), changes)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes[tool.Name].checksums, map[string]string{
+		"linux/amd64":  "sha256:" + sha256Hex("linux-1.1.0"),
+		"darwin/arm64": "SHA256:" + sha256Hex("darwin-1.1.0"),
+	})
+
+	t.Run("Rejects variables that depend on the current platform", func(t *testing.T) {
+		tool.AssetPattern = "{name}_{version}_{triple}"
+
+		err := b.pinChecksums(t.Context(), changes)
+		assert.ErrorContains(t, err, `cannot expand {triple} for platform "darwin/arm64"`)
+	})
+}
+
+// buildProvider is an installer writing the version of the bin as binary.
+type buildProvider struct{}
+
+func (buildProvider) downloadURL(context.Context, *bin) (string, error) {
+	return "", nil
+}
+
+func (buildProvider) latestVersion(context.Context, *bin) (string, error) {
+	return "", nil
+}
+
+func (buildProvider) install(_ context.Context, b *bin, binPath string) (string, error) {
+	return "", os.WriteFile(binPath, []byte("build-"+b.Version), 0o755)
+}
+
+func TestPinChecksumsInstaller(t *testing.T) {
+	modifyRuntime(t, "linux", "amd64")
+
+	b, tool := newLockTestBine(t, nil)
+	tool.provider = buildProvider{}
+	tool.Checksums = map[string]string{"linux/amd64": "sha256:" + sha256Hex("build-1.0.0")}
+	b.config.namer = &namer{}
+
+	changes := map[string]*binUpdate{tool.Name: {version: "1.1.0"}}
+	err := b.pinChecksums(t.Context(), changes)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes[tool.Name].checksums, map[string]string{
+		"linux/amd64": "sha256:" + sha256Hex("build-1.1.0"),
+	})
+
+	t.Run("Rejects other platforms", func(t *testing.T) {
+		tool.Checksums["darwin/arm64"] = "sha256:" + sha256Hex("build-1.0.0")
+
+		err := b.pinChecksums(t.Context(), changes)
+		assert.ErrorContains(t, err, `checksums["darwin/arm64"]: the binary is installed by the provider, it can only be pinned for "linux/amd64"`)
+	})
+}
@@ -1,12 +1,13 @@
 package bine
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -44,11 +45,14 @@ type config struct {
 	// namer is used to compute the asset names. This is set when the config
 	// is loaded and during the update process.
 	namer *namer
+
+	// lock is the lock file found next to the configuration file, if any.
+	lock *lockFile
 }
 
 // loadConfig loads the configuration file from the current working directory
 // or its parent directories.
-func loadConfig(ctx context.Context, client *http.Client, ghAPIToken string) (*config, error) {
+func loadConfig(ctx context.Context, opts providerOptions) (*config, error) {
 	curDir, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current working directory: %w", err)
@@ -71,6 +75,19 @@ func loadConfig(ctx context.Context, client *http.Client, ghAPIToken string) (*c
 	cfg.path = configFile.path
 	cfg.format = configFile.format
 	applyLibraryDefaults(cfg)
+	if err := resolveGoModVersions(cfg.path, cfg.Bins); err != nil {
+		return nil, err
+	}
+
+	// Paths in the configuration file are relative to its directory.
+	for _, b := range cfg.Bins {
+		if b.Signature != nil && b.Signature.Keyring != "" {
+			b.Signature.keyringPath = b.Signature.Keyring
+			if !filepath.IsAbs(b.Signature.keyringPath) {
+				b.Signature.keyringPath = filepath.Join(filepath.Dir(cfg.path), b.Signature.keyringPath)
+			}
+		}
+	}
 
 	if cfg.Project == "" {
 		return nil, fmt.Errorf("project name is empty in config file %q", configFile.path)
@@ -84,37 +101,64 @@ func loadConfig(ctx context.Context, client *http.Client, ghAPIToken string) (*c
 	}
 
 	for _, b := range cfg.Bins {
-		if err := b.loadProvider(client, ghAPIToken); err != nil {
+		if err := b.loadProvider(opts); err != nil {
 			return nil, fmt.Errorf("load provider for bin %q: %v", b.Name, err)
 		}
 	}
 
+	if lock, err := loadLockFile(lockFilePath(cfg.path)); err != nil {
+		return nil, err
+	} else {
+		cfg.lock = lock
+	}
+
 	return cfg, nil
 }
 
+// binUpdate describes the changes applied to a bin of the configuration file.
+type binUpdate struct {
+	// version is the new version, if it changed.
+	version string
+	// checksums replaces the values of the checksums field, keyed by
+	// platform.
+	checksums map[string]string
+}
+
 // update applies the updates to the configuration file when the format supports
 // in-place edits.
 func (c *config) update(updates []*ListItem) error {
-	if c.path == "" {
-		return errors.New("config path is not set")
-	}
+	return c.apply(c.changes(updates))
+}
 
-	changes := map[string]string{}
+// changes returns the version changes of the given updates keyed by bin name.
+func (c *config) changes(updates []*ListItem) map[string]*binUpdate {
+	changes := map[string]*binUpdate{}
 	for _, item := range updates {
 		for _, b := range c.Bins {
 			if b.Name == item.Name {
-				// "latest" bins are reinstalled without changing the config.
-				if b.isLatest() {
+				// "latest" bins and version ranges are reinstalled without
+				// changing the config, go_mod bins follow go.mod.
+				if b.isLatest() || b.isRange() || b.GoMod {
 					break
 				}
 				nextVersion := strings.TrimPrefix(item.Latest, "v")
 				if nextVersion != "" && b.Version != nextVersion {
-					changes[b.Name] = nextVersion
+					changes[b.Name] = &binUpdate{version: nextVersion}
 				}
 				break
 			}
 		}
 	}
+
+	return changes
+}
+
+// apply writes the changes to the configuration file and updates the loaded
+// configuration accordingly.
+func (c *config) apply(changes map[string]*binUpdate) error {
+	if c.path == "" {
+		return errors.New("config path is not set")
+	}
 	if len(changes) == 0 {
 		return nil
 	}
@@ -124,8 +168,13 @@ func (c *config) update(updates []*ListItem) error {
 	}
 
 	for _, b := range c.Bins {
-		if nextVersion, ok := changes[b.Name]; ok {
-			b.Version = nextVersion
+		if change, ok := changes[b.Name]; ok {
+			if change.version != "" {
+				b.Version = change.version
+			}
+			if change.checksums != nil {
+				b.Checksums = change.checksums
+			}
 		}
 	}
 	c.namer.run(c.Bins)
@@ -211,7 +260,7 @@ func unmarshalTOMLConfig(b []byte) (*config, error) {
 	return &c, nil
 }
 
-func updateConfigFile(path string, format configFormat, changes map[string]string) error {
+func updateConfigFile(path string, format configFormat, changes map[string]*binUpdate) error {
 	switch format {
 	case configFormatJSON:
 		return updateJSONConfigFile(path, changes)
@@ -222,7 +271,31 @@ func updateConfigFile(path string, format configFormat, changes map[string]strin
 	}
 }
 
-func updateJSONConfigFile(path string, changes map[string]string) error {
+func updateJSONConfigFile(path string, changes map[string]*binUpdate) error {
+	return editJSONConfigFile(path, func(tree *hujson.Value) error {
+		// Modify the version and checksums attributes using JSON Patch.
+		for i := 0; ; i++ {
+			if binNode := tree.Find(fmt.Sprintf("/bins/%d", i)); binNode == nil {
+				break
+			} else if nameNode := binNode.Find("/name"); nameNode == nil {
+				continue
+			} else if nameLiteral, ok := nameNode.Value.(hujson.Literal); !ok {
+				continue
+			} else if change, ok := changes[nameLiteral.String()]; !ok {
+				continue
+			} else if patch, err := jsonPatch(change); err != nil {
+				return err
+			} else if err := binNode.Patch(patch); err != nil {
+				return fmt.Errorf("patch replace: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// editJSONConfigFile applies edit to the hujson tree of the configuration
+// file, preserving comments and formatting.
+func editJSONConfigFile(path string, edit func(tree *hujson.Value) error) error {
 	f, err := os.OpenFile(path, os.O_RDWR, 0)
 	if err != nil {
 		return fmt.Errorf("open file: %v", err)
@@ -239,19 +312,8 @@ func updateJSONConfigFile(path string, changes map[string]string) error {
 		return fmt.Errorf("hujson parse: %v", err)
 	}
 
-	// Modify the version attribute using JSON Patch.
-	for i := 0; ; i++ {
-		if binNode := tree.Find(fmt.Sprintf("/bins/%d", i)); binNode == nil {
-			break
-		} else if nameNode := binNode.Find("/name"); nameNode == nil {
-			continue
-		} else if nameLiteral, ok := nameNode.Value.(hujson.Literal); !ok {
-			continue
-		} else if latest, ok := changes[nameLiteral.String()]; !ok {
-			continue
-		} else if err := binNode.Patch(fmt.Appendf(nil, `[{"op": "replace", "path": "/version", "value": "%s"}]`, latest)); err != nil {
-			return fmt.Errorf("patch replace: %v", err)
-		}
+	if err := edit(&tree); err != nil {
+		return err
 	}
 
 	// Write the modified tree back to the file and truncate it to the new size.
@@ -269,11 +331,153 @@ func updateJSONConfigFile(path string, changes map[string]string) error {
 	return f.Sync()
 }
 
+// jsonPatch returns the JSON Patch document that applies the change to a bin.
+func jsonPatch(change *binUpdate) ([]byte, error) {
+	type operation struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+
+	var ops []operation
+	if change.version != "" {
+		ops = append(ops, operation{"replace", "/version", change.version})
+	}
+	pointer := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, platform := range slices.Sorted(maps.Keys(change.checksums)) {
+		ops = append(ops, operation{"replace", "/checksums/" + pointer.Replace(platform), change.checksums[platform]})
+	}
+
+	blob, err := json.Marshal(ops)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %v", err)
+	}
+
+	return blob, nil
+}
+
+// appendBins adds the entries to the bins of the configuration file.
+func (c *config) appendBins(entries []any) error {
+	if c.path == "" {
+		return errors.New("config path is not set")
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	switch c.format {
+	case configFormatJSON:
+		return appendJSONConfigFile(c.path, entries)
+	case configFormatTOML:
+		return appendTOMLConfigFile(c.path, entries)
+	default:
+		return fmt.Errorf("unsupported config format %q", c.format)
+	}
+}
+
+// appendJSONConfigFile adds the entries at the end of the bins array,
+// indented like the existing ones.
+func appendJSONConfigFile(path string, entries []any) error {
+	return editJSONConfigFile(path, func(tree *hujson.Value) error {
+		if tree.Find("/bins") == nil {
+			if err := tree.Patch([]byte(`[{"op": "add", "path": "/bins", "value": []}]`)); err != nil {
+				return fmt.Errorf("patch add: %v", err)
+			}
+		}
+		bins, ok := tree.Find("/bins").Value.(*hujson.Array)
+		if !ok {
+			return errors.New("bins is not an array")
+		}
+
+		// Guess the indentation from the last bin, if any, nested two levels
+		// deep. Only whitespace is copied, the comments of the last bin
+		// belong to it.
+		prefix, indent := "\t\t", "\t"
+		if n := len(bins.Elements); n > 0 {
+			before := bins.Elements[n-1].BeforeExtra
+			line := before[bytes.LastIndexByte(before, '\n')+1:]
+			prefix = string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+			if len(prefix) >= 2 {
+				indent = prefix[:len(prefix)/2]
+			}
+		} else if len(bins.AfterExtra) == 0 {
+			bins.AfterExtra = []byte("\n\t")
+		}
+
+		for _, entry := range entries {
+			blob, err := json.MarshalIndent(entry, prefix, indent)
+			if err != nil {
+				return fmt.Errorf("json marshal: %v", err)
+			}
+			value, err := hujson.Parse(blob)
+			if err != nil {
+				return fmt.Errorf("hujson parse: %v", err)
+			}
+			value.BeforeExtra = []byte("\n" + prefix)
+			bins.Elements = append(bins.Elements, value)
+		}
+
+		return nil
+	})
+}
+
+// appendTOMLConfigFile adds the entries as [[bins]] tables at the end of the
+// file.
+func appendTOMLConfigFile(path string, entries []any) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat file: %v", err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read file: %v", err)
+	}
+	before, err := unmarshalTOMLConfig(contents)
+	if err != nil {
+		return fmt.Errorf("parse TOML: %v", err)
+	}
+
+	blob, err := toml.Marshal(struct {
+		Bins []any `toml:"bins"`
+	}{entries})
+	if err != nil {
+		return fmt.Errorf("toml marshal: %v", err)
+	}
+
+	updated := bytes.TrimRight(contents, "\n")
+	if len(updated) > 0 {
+		updated = append(updated, "\n\n"...)
+	}
+	updated = append(updated, blob...)
+
+	// Appending tables only works when the existing bins are tables too.
+	if after, err := unmarshalTOMLConfig(updated); err != nil || len(after.Bins) != len(before.Bins)+len(entries) {
+		return errors.New("adding bins is only supported for TOML configs using [[bins]] tables")
+	}
+
+	perm := info.Mode().Perm()
+	if err := renameio.WriteFile(path, updated, perm, renameio.WithStaticPermissions(perm)); err != nil {
+		return fmt.Errorf("write file: %v", err)
+	}
+
+	return nil
+}
+
 type tomlBinTable struct {
 	name         string
 	versionRange unstable.Range
 	versionRaw   []byte
 	directKeys   bool
+	// checksumsTable is set while parsing a [bins.checksums] table.
+	checksumsTable bool
+	checksums      map[string]tomlString
+}
+
+// tomlString is a string value found in the TOML document.
+type tomlString struct {
+	rng unstable.Range
+	raw []byte
 }
 
 type tomlReplacement struct {
@@ -282,7 +486,7 @@ type tomlReplacement struct {
 	text  string
 }
 
-func updateTOMLConfigFile(path string, changes map[string]string) error {
+func updateTOMLConfigFile(path string, changes map[string]*binUpdate) error {
 	info, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("stat file: %v", err)
@@ -309,30 +513,68 @@ func updateTOMLConfigFile(path string, changes map[string]string) error {
 		}
 		defer func() { current = nil }()
 
-		nextVersion, ok := changes[current.name]
+		change, ok := changes[current.name]
 		if !ok {
 			return nil
 		}
-		if len(current.versionRaw) == 0 {
-			return fmt.Errorf("upgrade is only supported for TOML [[bins]] tables with explicit string version keys; %q is missing one", current.name)
+
+		if change.version != "" {
+			if len(current.versionRaw) == 0 {
+				return fmt.Errorf("upgrade is only supported for TOML [[bins]] tables with explicit string version keys; %q is missing one", current.name)
+			}
+
+			versionLiteral, err := tomlStringLiteral(change.version, current.versionRaw)
+			if err != nil {
+				return fmt.Errorf("upgrade TOML version for %q: %v", current.name, err)
+			}
+
+			start := int(current.versionRange.Offset)
+			end := start + int(current.versionRange.Length)
+			replacements = append(replacements, tomlReplacement{
+				start: start,
+				end:   end,
+				text:  versionLiteral,
+			})
 		}
 
-		versionLiteral, err := tomlStringLiteral(nextVersion, current.versionRaw)
-		if err != nil {
-			return fmt.Errorf("upgrade TOML version for %q: %v", current.name, err)
+		for platform, digest := range change.checksums {
+			value, ok := current.checksums[platform]
+			if !ok {
+				return fmt.Errorf("failed to locate TOML checksum key %q for %q", platform, current.name)
+			}
+
+			checksumLiteral, err := tomlStringLiteral(digest, value.raw)
+			if err != nil {
+				return fmt.Errorf("upgrade TOML checksum for %q: %v", current.name, err)
+			}
+
+			start := int(value.rng.Offset)
+			end := start + int(value.rng.Length)
+			replacements = append(replacements, tomlReplacement{
+				start: start,
+				end:   end,
+				text:  checksumLiteral,
+			})
 		}
 
-		start := int(current.versionRange.Offset)
-		end := start + int(current.versionRange.Length)
-		replacements = append(replacements, tomlReplacement{
-			start: start,
-			end:   end,
-			text:  versionLiteral,
-		})
 		applied[current.name] = true
 		return nil
 	}
 
+	addChecksum := func(platform string, value *unstable.Node) error {
+		if value.Kind != unstable.String {
+			return errors.New("upgrade is only supported for TOML string checksum values")
+		}
+		if current.checksums == nil {
+			current.checksums = map[string]tomlString{}
+		}
+		current.checksums[platform] = tomlString{
+			rng: value.Raw,
+			raw: slices.Clone(parser.Raw(value.Raw)),
+		}
+		return nil
+	}
+
 	for parser.NextExpression() {
 		expr := parser.Expression()
 		switch expr.Kind {
@@ -352,6 +594,7 @@ func updateTOMLConfigFile(path string, changes map[string]string) error {
 			path := tomlKeyPath(expr)
 			if current != nil && len(path) > 0 && path[0] == "bins" {
 				current.directKeys = false
+				current.checksumsTable = slices.Equal(path, []string{"bins", "checksums"})
 				continue
 			}
 			if err := flush(); err != nil {
@@ -362,21 +605,41 @@ func updateTOMLConfigFile(path string, changes map[string]string) error {
 			if len(keyPath) == 1 && keyPath[0] == "bins" {
 				sawUnsupportedLayout = true
 			}
-			if current == nil || !current.directKeys || len(keyPath) != 1 {
+			if current == nil {
+				continue
+			}
+			if !current.directKeys {
+				if current.checksumsTable && len(keyPath) == 1 {
+					if err := addChecksum(keyPath[0], expr.Value()); err != nil {
+						return err
+					}
+				}
 				continue
 			}
 
-			switch keyPath[0] {
-			case "name":
+			switch {
+			case len(keyPath) == 1 && keyPath[0] == "name":
 				if expr.Value().Kind == unstable.String {
 					current.name = string(expr.Value().Data)
 				}
-			case "version":
+			case len(keyPath) == 1 && keyPath[0] == "version":
 				if expr.Value().Kind != unstable.String {
 					return fmt.Errorf("upgrade is only supported for TOML string version keys in [[bins]] tables")
 				}
 				current.versionRange = expr.Value().Raw
 				current.versionRaw = slices.Clone(parser.Raw(expr.Value().Raw))
+			case len(keyPath) == 1 && keyPath[0] == "checksums" && expr.Value().Kind == unstable.InlineTable:
+				for it := expr.Value().Children(); it.Next(); {
+					if path := tomlKeyPath(it.Node()); len(path) == 1 {
+						if err := addChecksum(path[0], it.Node().Value()); err != nil {
+							return err
+						}
+					}
+				}
+			case len(keyPath) == 2 && keyPath[0] == "checksums":
+				if err := addChecksum(keyPath[1], expr.Value()); err != nil {
+					return err
+				}
 			}
 		default:
 			if err := flush(); err != nil {
@@ -75,7 +75,7 @@ func TestConfigUpdate(t *testing.T) {
 		tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", configDoc))
 
 		t.Chdir(tmpDir.Path())
-		cfg, err := loadConfig(t.Context(), nil, "")
+		cfg, err := loadConfig(t.Context(), providerOptions{})
 		assert.NilError(t, err)
 
 		err = cfg.update([]*ListItem{{Name: "perpignan", Latest: "1.1.0"}})
@@ -129,7 +129,7 @@ asset_pattern = "{name}_{version}_{goos}_{goarch}"
 
 	t.Chdir(tmpDir.Path())
 
-	cfg, err := loadConfig(t.Context(), nil, "")
+	cfg, err := loadConfig(t.Context(), providerOptions{})
 	assert.NilError(t, err)
 	assert.Equal(t, cfg.Project, "test")
 	assert.Equal(t, cfg.path, tmpDir.Join(".bine.toml"))
@@ -140,6 +140,87 @@ asset_pattern = "{name}_{version}_{goos}_{goarch}"
 	assert.Equal(t, cfg.Bins[0].AssetPattern, "{name}_{version}_{goos}_{goarch}")
 }
 
+func TestLoadConfigChannel(t *testing.T) {
+	t.Run("Includes prereleases of every bin when requested", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", `{
+			"project": "test",
+			"bins": [
+				{"name": "perpignan", "url": "https://github.com/sevein/perpignan", "version": "1.0.0", "channel": "stable"}
+			]
+		}`))
+		t.Chdir(tmpDir.Path())
+
+		cfg, err := loadConfig(t.Context(), providerOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, cfg.Bins[0].prereleases(), false)
+
+		cfg, err = loadConfig(t.Context(), providerOptions{includePrereleases: true})
+		assert.NilError(t, err)
+		assert.Equal(t, cfg.Bins[0].prereleases(), true)
+	})
+
+	t.Run("Rejects unknown channels", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", `{
+			"project": "test",
+			"bins": [
+				{"name": "perpignan", "url": "https://github.com/sevein/perpignan", "version": "1.0.0", "channel": "beta"}
+			]
+		}`))
+		t.Chdir(tmpDir.Path())
+
+		_, err := loadConfig(t.Context(), providerOptions{})
+		assert.Error(t, err, `load provider for bin "perpignan": invalid channel "beta", expected "stable" or "prerelease"`)
+	})
+}
+
+func TestLoadConfigSignature(t *testing.T) {
+	tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.toml", `project = "test"
+
+[[bins]]
+name = "perpignan"
+url = "https://github.com/sevein/perpignan"
+version = "1.0.0"
+asset_pattern = "{name}_{version}_{goos}_{goarch}"
+
+[bins.signature]
+pattern = "{name}_{version}_{goos}_{goarch}.minisig"
+public_key = "RWQBAgMEBQYHCA=="
+
+[[bins]]
+name = "terraform"
+url = "https://github.com/hashicorp/terraform"
+version = "1.0.0"
+asset_pattern = "{name}_{version}_{goos}_{goarch}.zip"
+checksum_pattern = "{name}_{version}_SHA256SUMS"
+
+[bins.signature]
+format = "openpgp"
+pattern = "{name}_{version}_SHA256SUMS.sig"
+target = "checksums"
+keyring = "keys/hashicorp.asc"
+`))
+
+	t.Chdir(tmpDir.Path())
+	modifyRuntime(t, "linux", "amd64")
+
+	cfg, err := loadConfig(t.Context(), providerOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cfg.Bins[0].Signature, &binSignature{
+		Pattern:   "{name}_{version}_{goos}_{goarch}.minisig",
+		PublicKey: "RWQBAgMEBQYHCA==",
+	}, cmpopts.IgnoreUnexported(binSignature{}))
+	assert.Equal(t, cfg.Bins[0].signatureAsset, "perpignan_1.0.0_linux_amd64.minisig")
+
+	assert.DeepEqual(t, cfg.Bins[1].Signature, &binSignature{
+		Format:  "openpgp",
+		Pattern: "{name}_{version}_SHA256SUMS.sig",
+		Target:  "checksums",
+		Keyring: "keys/hashicorp.asc",
+	}, cmpopts.IgnoreUnexported(binSignature{}))
+	assert.Equal(t, cfg.Bins[1].Signature.keyringPath, tmpDir.Join("keys", "hashicorp.asc"))
+	assert.Equal(t, cfg.Bins[1].signatureAsset, "terraform_1.0.0_SHA256SUMS.sig")
+}
+
 func TestConfigUpdateTOML(t *testing.T) {
 	tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.toml", `# Top comment.
 project = "test"
@@ -192,6 +273,179 @@ asset_pattern = "{name}_{version}_{os}_{arch}.tar.gz"
 `))
 }
 
+func TestConfigAppendBinsJSON(t *testing.T) {
+	tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", `{
+    "project": "test",
+    "bins": [
+        // Linter used in CI.
+        {
+            "name": "golangci-lint",
+            "version": "1.61.0"
+        }
+    ]
+}`))
+
+	cfg := &config{path: tmpDir.Join(".bine.json"), format: configFormatJSON}
+	err := cfg.appendBins([]any{
+		map[string]string{"name": "migrate"},
+		map[string]string{"name": "sqlc"},
+	})
+	assert.NilError(t, err)
+
+	contents, err := os.ReadFile(tmpDir.Join(".bine.json"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(contents), `{
+    "project": "test",
+    "bins": [
+        // Linter used in CI.
+        {
+            "name": "golangci-lint",
+            "version": "1.61.0"
+        },
+        {
+            "name": "migrate"
+        },
+        {
+            "name": "sqlc"
+        }
+    ]
+}`)
+}
+
+func TestConfigApplyChecksums(t *testing.T) {
+	sha256A := "sha256:" + sha256Hex("a")
+	sha256B := "sha256:" + sha256Hex("b")
+
+	t.Run("Rewrites TOML checksums", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.toml", `project = "test"
+
+[[bins]]
+name = "inline"
+version = "1.0.0"
+checksums = { "linux/amd64" = "`+sha256A+`", "darwin/arm64" = '`+sha256A+`' }
+
+[[bins]]
+name = "dotted"
+version = "1.0.0"
+checksums."linux/amd64" = "`+sha256A+`"
+
+[[bins]]
+name = "table"
+version = "1.0.0"
+
+[bins.checksums]
+"linux/amd64" = "`+sha256A+`" # Keep trailing comment.
+`))
+
+		cfg := &config{
+			Project: "test",
+			Bins: []*bin{
+				{Name: "inline", Version: "1.0.0"},
+				{Name: "dotted", Version: "1.0.0"},
+				{Name: "table", Version: "1.0.0"},
+			},
+			path:   tmpDir.Join(".bine.toml"),
+			format: configFormatTOML,
+			namer:  &namer{},
+		}
+
+		err := cfg.apply(map[string]*binUpdate{
+			"inline": {version: "1.1.0", checksums: map[string]string{"linux/amd64": sha256B, "darwin/arm64": sha256B}},
+			"dotted": {checksums: map[string]string{"linux/amd64": sha256B}},
+			"table":  {version: "1.1.0", checksums: map[string]string{"linux/amd64": sha256B}},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, cfg.Bins[0].Checksums, map[string]string{"linux/amd64": sha256B, "darwin/arm64": sha256B})
+
+		contents, err := os.ReadFile(tmpDir.Join(".bine.toml"))
+		assert.NilError(t, err)
+		assert.Equal(t, string(contents), `project = "test"
+
+[[bins]]
+name = "inline"
+version = "1.1.0"
+checksums = { "linux/amd64" = "`+sha256B+`", "darwin/arm64" = '`+sha256B+`' }
+
+[[bins]]
+name = "dotted"
+version = "1.0.0"
+checksums."linux/amd64" = "`+sha256B+`"
+
+[[bins]]
+name = "table"
+version = "1.1.0"
+
+[bins.checksums]
+"linux/amd64" = "`+sha256B+`" # Keep trailing comment.
+`)
+	})
+
+	t.Run("Rewrites JSON checksums", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", `{
+	"project": "test",
+	"bins": [
+		{
+			"name": "tool",
+			"version": "1.0.0",
+			// Pinned per platform.
+			"checksums": {
+				"linux/amd64": "`+sha256A+`",
+			},
+		},
+	],
+}`))
+
+		cfg := &config{
+			Project: "test",
+			Bins:    []*bin{{Name: "tool", Version: "1.0.0"}},
+			path:    tmpDir.Join(".bine.json"),
+			format:  configFormatJSON,
+			namer:   &namer{},
+		}
+
+		err := cfg.apply(map[string]*binUpdate{
+			"tool": {version: "1.1.0", checksums: map[string]string{"linux/amd64": sha256B}},
+		})
+		assert.NilError(t, err)
+
+		contents, err := os.ReadFile(tmpDir.Join(".bine.json"))
+		assert.NilError(t, err)
+		assert.Equal(t, string(contents), `{
+	"project": "test",
+	"bins": [
+		{
+			"name": "tool",
+			"version": "1.1.0",
+			// Pinned per platform.
+			"checksums": {
+				"linux/amd64": "`+sha256B+`",
+			},
+		},
+	],
+}`)
+	})
+
+	t.Run("Fails when a TOML checksum key is missing", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.toml", `project = "test"
+
+[[bins]]
+name = "tool"
+version = "1.0.0"
+`))
+
+		cfg := &config{
+			Bins:   []*bin{{Name: "tool", Version: "1.0.0"}},
+			path:   tmpDir.Join(".bine.toml"),
+			format: configFormatTOML,
+		}
+
+		err := cfg.apply(map[string]*binUpdate{
+			"tool": {checksums: map[string]string{"linux/amd64": sha256B}},
+		})
+		assert.Error(t, err, `failed to locate TOML checksum key "linux/amd64" for "tool"`)
+	})
+}
+
 func TestConfigUpdateRejectsUnsupportedTOMLLayout(t *testing.T) {
 	tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.toml", `project = "test"
 
@@ -255,13 +509,13 @@ func TestConfigModifiers(t *testing.T) {
 
 		modifyRuntime(t, "darwin", "arm64")
 
-		cfg, err := loadConfig(t.Context(), nil, "")
+		cfg, err := loadConfig(t.Context(), providerOptions{})
 		assert.NilError(t, err)
 
 		// grpcurl leverages the modifiers.
 		bin := cfg.Bins[0]
 		{
-			url, err := bin.provider.downloadURL(bin)
+			url, err := bin.provider.downloadURL(t.Context(), bin)
 			assert.NilError(t, err)
 			assert.Equal(t, url, "https://github.com/fullstorydev/grpcurl/releases/download/v1.9.3/grpcurl_1.9.3_osx_arm64.tar.gz")
 		}
@@ -269,7 +523,7 @@ func TestConfigModifiers(t *testing.T) {
 		// perpignan still works without modifiers.
 		bin = cfg.Bins[1]
 		{
-			url, err := bin.provider.downloadURL(bin)
+			url, err := bin.provider.downloadURL(t.Context(), bin)
 			assert.NilError(t, err)
 			assert.Equal(t, url, "https://github.com/sevein/perpignan/releases/download/v1.0.0/perpignan_1.0.0_darwin_arm64")
 		}
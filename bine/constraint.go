@@ -0,0 +1,245 @@
+package bine
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// versionConstraint is a range of semver versions, e.g. "^1.62", "~2.0.2",
+// ">=1.7 <2" or "1.x". Alternatives are separated by "||" and the comparators
+// of an alternative must all be satisfied.
+type versionConstraint struct {
+	raw  string
+	sets [][]versionComparator
+}
+
+// versionComparator compares versions with a canonical semver version.
+type versionComparator struct {
+	op      string
+	version string
+}
+
+// wildcardVersion matches versions ending with a wildcard, e.g. "1.x".
+var wildcardVersion = regexp.MustCompile(`(^|\.)[xX*]$`)
+
+// isVersionConstraint reports whether the version configured for a bin is a
+// range rather than an exact version.
+func isVersionConstraint(version string) bool {
+	return strings.ContainsAny(version, "^~<>=*|, ") || wildcardVersion.MatchString(version)
+}
+
+// parseVersionConstraint parses a range of versions. Comparators are
+// separated by spaces or commas, and partial versions are completed with
+// zeros, e.g. ">=1.7" is ">=1.7.0". The upper bounds of the ranges exclude
+// the prereleases of the bound, e.g. "^1.62" doesn't match "2.0.0-rc.1".
+func parseVersionConstraint(raw string) (*versionConstraint, error) {
+	c := &versionConstraint{raw: raw}
+	for alternative := range strings.SplitSeq(raw, "||") {
+		fields := strings.FieldsFunc(alternative, func(r rune) bool { return r == ' ' || r == ',' })
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version range %q", raw)
+		}
+		var set []versionComparator
+		for _, field := range fields {
+			comparators, err := parseVersionComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %v", raw, err)
+			}
+			set = append(set, comparators...)
+		}
+		c.sets = append(c.sets, set)
+	}
+
+	return c, nil
+}
+
+// parseVersionComparator returns the comparators equivalent to a single
+// element of a range, e.g. "^1.2" is ">=1.2.0 <2.0.0-0".
+func parseVersionComparator(s string) ([]versionComparator, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			op, s = prefix, rest
+			break
+		}
+	}
+
+	v, err := parsePartialVersion(s)
+	if err != nil {
+		return nil, err
+	}
+	if op != "" && op != "=" && v.parts == 0 {
+		return nil, fmt.Errorf("%q cannot be used with a wildcard", op)
+	}
+
+	switch op {
+	case ">=":
+		return []versionComparator{{">=", v.lower()}}, nil
+	case "<":
+		return []versionComparator{{"<", v.lowerBound()}}, nil
+	case ">":
+		if v.parts < 3 {
+			return []versionComparator{{">=", v.next(v.parts).lower()}}, nil
+		}
+		return []versionComparator{{">", v.lower()}}, nil
+	case "<=":
+		if v.parts < 3 {
+			return []versionComparator{{"<", v.next(v.parts).lowerBound()}}, nil
+		}
+		return []versionComparator{{"<=", v.lower()}}, nil
+	case "^":
+		// The first non-zero component can't change, e.g. "^0.3" allows
+		// patches only.
+		upper := v.parts
+		switch {
+		case v.major != 0 || v.parts == 1:
+			upper = 1
+		case v.minor != 0 || v.parts == 2:
+			upper = 2
+		}
+		return []versionComparator{{">=", v.lower()}, {"<", v.next(upper).lowerBound()}}, nil
+	case "~":
+		upper := min(v.parts, 2)
+		return []versionComparator{{">=", v.lower()}, {"<", v.next(upper).lowerBound()}}, nil
+	default:
+		switch v.parts {
+		case 0:
+			return []versionComparator{{">=", "v0.0.0"}}, nil
+		case 3:
+			return []versionComparator{{"=", v.lower()}}, nil
+		default:
+			return []versionComparator{{">=", v.lower()}, {"<", v.next(v.parts).lowerBound()}}, nil
+		}
+	}
+}
+
+// partialVersion is a version with up to three numeric components, e.g. "1.2"
+// or "1.x", and a prerelease when all of them are given.
+type partialVersion struct {
+	major, minor, patch int
+	// parts is the number of components given, wildcards excluded.
+	parts      int
+	prerelease string
+}
+
+func parsePartialVersion(s string) (partialVersion, error) {
+	var v partialVersion
+	version := strings.TrimPrefix(s, "v")
+	if version == "" {
+		return v, fmt.Errorf("missing version in %q", s)
+	}
+	if core, prerelease, ok := strings.Cut(version, "-"); ok {
+		version, v.prerelease = core, "-"+prerelease
+	}
+
+	components := strings.Split(version, ".")
+	if len(components) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	wildcard := false
+	for i, component := range components {
+		if component == "x" || component == "X" || component == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		n, err := strconv.Atoi(component)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		switch i {
+		case 0:
+			v.major = n
+		case 1:
+			v.minor = n
+		case 2:
+			v.patch = n
+		}
+		v.parts++
+	}
+	if v.prerelease != "" && v.parts < 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	if v.prerelease != "" && !semver.IsValid(v.lower()) {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+
+	return v, nil
+}
+
+// lower returns the lowest version matched by the partial version.
+func (v partialVersion) lower() string {
+	return fmt.Sprintf("v%d.%d.%d%s", v.major, v.minor, v.patch, v.prerelease)
+}
+
+// lowerBound is like lower, but it also excludes the prereleases of the
+// version when used as an exclusive upper bound.
+func (v partialVersion) lowerBound() string {
+	if v.prerelease != "" {
+		return v.lower()
+	}
+	return v.lower() + "-0"
+}
+
+// next returns the version that follows every version matching the first n
+// components of v, e.g. 1.3.0 for 1.2 and n = 2.
+func (v partialVersion) next(n int) partialVersion {
+	switch n {
+	case 1:
+		return partialVersion{major: v.major + 1, parts: 3}
+	case 2:
+		return partialVersion{major: v.major, minor: v.minor + 1, parts: 3}
+	default:
+		return partialVersion{major: v.major, minor: v.minor, patch: v.patch + 1, parts: 3}
+	}
+}
+
+// check reports whether the version, with or without "v" prefix, is in the
+// range.
+func (c *versionConstraint) check(version string) bool {
+	canonical := semver.Canonical("v" + strings.TrimPrefix(version, "v"))
+	if canonical == "" {
+		return false
+	}
+
+	for _, set := range c.sets {
+		ok := true
+		for _, comparator := range set {
+			if !comparator.check(canonical) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c versionComparator) check(version string) bool {
+	cmp := semver.Compare(version, c.version)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+func (c *versionConstraint) String() string {
+	return c.raw
+}
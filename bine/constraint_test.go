@@ -0,0 +1,193 @@
+package bine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{
+			constraint: "^1.62",
+			match:      []string{"1.62.0", "v1.62.3", "1.99.0"},
+			noMatch:    []string{"1.61.9", "2.0.0", "2.0.0-rc.1", "1.62.0-rc.1"},
+		},
+		{
+			constraint: "^0.3.1",
+			match:      []string{"0.3.1", "0.3.9"},
+			noMatch:    []string{"0.3.0", "0.4.0"},
+		},
+		{
+			constraint: "^0.0.3",
+			match:      []string{"0.0.3"},
+			noMatch:    []string{"0.0.4"},
+		},
+		{
+			constraint: "~2.0.2",
+			match:      []string{"2.0.2", "2.0.9"},
+			noMatch:    []string{"2.0.1", "2.1.0"},
+		},
+		{
+			constraint: ">=1.7 <2",
+			match:      []string{"1.7.0", "1.9.9"},
+			noMatch:    []string{"1.6.9", "2.0.0", "2.0.0-beta.1"},
+		},
+		{
+			constraint: ">1.7, <=1.9",
+			match:      []string{"1.8.0", "1.9.5"},
+			noMatch:    []string{"1.7.9", "1.10.0"},
+		},
+		{
+			constraint: "1.x",
+			match:      []string{"1.0.0", "1.99.1"},
+			noMatch:    []string{"0.9.0", "2.0.0"},
+		},
+		{
+			constraint: "1.2.x || 1.4.*",
+			match:      []string{"1.2.3", "1.4.0"},
+			noMatch:    []string{"1.3.0", "1.5.0"},
+		},
+		{
+			constraint: "*",
+			match:      []string{"0.0.1", "3.0.0"},
+			noMatch:    []string{"latest"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.constraint, func(t *testing.T) {
+			assert.Assert(t, isVersionConstraint(tc.constraint))
+
+			c, err := parseVersionConstraint(tc.constraint)
+			assert.NilError(t, err)
+			for _, version := range tc.match {
+				assert.Assert(t, c.check(version), "%s should match %s", tc.constraint, version)
+			}
+			for _, version := range tc.noMatch {
+				assert.Assert(t, !c.check(version), "%s should not match %s", tc.constraint, version)
+			}
+		})
+	}
+
+	for _, version := range []string{"1.2.3", "v0.30.0", "1.62", "latest", "1.0.0-next"} {
+		assert.Assert(t, !isVersionConstraint(version), version)
+	}
+
+	_, err := parseVersionConstraint(">=1.x.2")
+	assert.Error(t, err, `invalid version range ">=1.x.2": invalid version "1.x.2"`)
+	_, err = parseVersionConstraint("^*")
+	assert.Error(t, err, `invalid version range "^*": "^" cannot be used with a wildcard`)
+	_, err = parseVersionConstraint("1.2 ||")
+	assert.Error(t, err, `invalid version range "1.2 ||"`)
+}
+
+func TestVersionRange(t *testing.T) {
+	versions := []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/versions.json" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"versions": versions})
+			return
+		}
+		version, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/tool/"), "/tool")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("tool " + version))
+	}))
+	defer server.Close()
+
+	const config = `{
+		"project": "test",
+		"bins": [
+			{
+				"name": "tool",
+				"version": "^1.1",
+				"download_url": "%[1]s/tool/{version}/tool",
+				"versions_url": "%[1]s/versions.json",
+				"versions_json_path": "versions.*"
+			}
+		]
+	}`
+	tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", fmt.Sprintf(config, server.URL)))
+	t.Chdir(tmpDir.Path())
+	cacheDir := t.TempDir()
+
+	newBine := func(t *testing.T) *Bine {
+		t.Helper()
+		b, err := NewWithOptions(WithCacheDir(cacheDir))
+		assert.NilError(t, err)
+		return b
+	}
+	installed := func(t *testing.T, b *Bine) string {
+		t.Helper()
+		blob, err := os.ReadFile(filepath.Join(b.BinDir, "tool"))
+		assert.NilError(t, err)
+		return string(blob)
+	}
+
+	b := newBine(t)
+	_, err := b.Get(t.Context(), "tool")
+	assert.NilError(t, err)
+	assert.Equal(t, installed(t, b), "tool 1.2.0")
+
+	tool, err := b.load("tool")
+	assert.NilError(t, err)
+	marker, err := b.readVersionMarker(tool)
+	assert.NilError(t, err)
+	assert.Equal(t, marker.ResolvedVersion, "1.2.0")
+
+	// Up to date in range, but a newer version is out of range.
+	items, err := b.List(t.Context(), false, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(items), 1)
+	assert.Equal(t, items[0].Version, "v1.2.0")
+	assert.Equal(t, items[0].Latest, "")
+	assert.Equal(t, items[0].LatestOverall, "v2.0.0")
+
+	updates, err := b.Upgrade(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, len(updates), 0)
+	assert.Equal(t, installed(t, b), "tool 1.2.0")
+
+	versions = append(versions, "1.3.0", "2.1.0-rc.1")
+	items, err = b.List(t.Context(), false, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(items), 1)
+	assert.Equal(t, items[0].Version, "v1.2.0")
+	assert.Equal(t, items[0].Latest, "v1.3.0")
+	assert.Equal(t, items[0].LatestOverall, "v2.0.0")
+
+	// Upgrades don't rewrite the range.
+	_, err = b.Upgrade(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, installed(t, b), "tool 1.3.0")
+	blob, err := os.ReadFile(tmpDir.Join(".bine.json"))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(blob), `"version": "^1.1"`))
+
+	// The lock file pins the version the range resolves to.
+	assert.NilError(t, b.Lock(t.Context()))
+	lock, err := loadLockFile(tmpDir.Join(lockFileName))
+	assert.NilError(t, err)
+	assert.Equal(t, lock.Bins["tool"].Version, "1.3.0")
+	assert.Equal(t, lock.Bins["tool"].Constraint, "^1.1")
+
+	versions = append(versions, "1.4.0")
+	assert.NilError(t, os.RemoveAll(b.VersionsDir))
+	b = newBine(t)
+	assert.NilError(t, b.Sync(t.Context()))
+	assert.Equal(t, installed(t, b), "tool 1.3.0")
+}
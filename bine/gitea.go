@@ -0,0 +1,114 @@
+package bine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// giteaProvider downloads release assets published on Gitea-compatible forges
+// such as Codeberg or self-hosted Forgejo instances.
+type giteaProvider struct {
+	client *http.Client
+
+	// apiURL is the base URL of the REST API, e.g. "https://codeberg.org/api/v1".
+	apiURL string
+	owner  string
+	repo   string
+}
+
+var _ binProvider = &giteaProvider{}
+
+func newGiteaProvider(client *http.Client, repoURL string) (*giteaProvider, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %v", err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Host == "" || len(parts) != 2 {
+		return nil, fmt.Errorf("could not extract owner/repo from %q", repoURL)
+	}
+
+	return &giteaProvider{
+		client: client,
+		apiURL: fmt.Sprintf("%s://%s/api/v1", u.Scheme, u.Host),
+		owner:  parts[0],
+		repo:   parts[1],
+	}, nil
+}
+
+type giteaRelease struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+func (p *giteaProvider) downloadURL(_ context.Context, b *bin) (string, error) {
+	return fmt.Sprintf("%s/releases/download/%s/%s", strings.TrimSuffix(b.URL, "/"), b.tag(), b.asset), nil
+}
+
+// latestVersion reads the releases, newest first, until a page has a version
+// considered by the bin, up to defaultReleasePages pages.
+func (p *giteaProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
+	var tags []string
+	next := fmt.Sprintf("%s/repos/%s/%s/releases?draft=false&limit=50", p.apiURL, url.PathEscape(p.owner), url.PathEscape(p.repo))
+	for page := 0; next != "" && page < defaultReleasePages; page++ {
+		var releases []giteaRelease
+		link, err := p.fetch(ctx, next, &releases)
+		if err != nil {
+			return "", err
+		}
+
+		for _, release := range releases {
+			if release.Draft || release.Prerelease && !bin.prereleases() {
+				continue
+			}
+			tags = append(tags, release.TagName)
+		}
+
+		if latestTaggedVersion(bin, tags) != "" {
+			break
+		}
+		if next, err = nextPage(next, link); err != nil {
+			return "", err
+		}
+	}
+
+	latestVersion := latestTaggedVersion(bin, tags)
+	if latestVersion == "" {
+		return "", fmt.Errorf("no valid %s tags found in Gitea releases matching tag pattern", bin.versionKind())
+	}
+
+	return latestVersion, nil
+}
+
+// fetch sends a request to the Gitea API and decodes the JSON response in v.
+// It returns the Link header of the response used for pagination.
+func (p *giteaProvider) fetch(ctx context.Context, url string, v any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Gitea API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode Gitea API response: %v", err)
+	}
+
+	return resp.Header.Get("Link"), nil
+}
@@ -0,0 +1,117 @@
+package bine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGiteaProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/tool/releases":
+			releases := []giteaRelease{
+				{TagName: "v2.0.0", Draft: true},
+				{TagName: "v1.1.0-rc.1", Prerelease: true},
+				{TagName: "v1.0.1"},
+				{TagName: "v1.0.0"},
+			}
+			_ = json.NewEncoder(w).Encode(releases)
+		case "/owner/tool/releases/download/v1.0.1/tool_1.0.1_linux_amd64.tar.gz":
+			_, _ = w.Write([]byte("asset"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := newGiteaProvider(server.Client(), server.URL+"/owner/tool")
+	assert.NilError(t, err)
+
+	tool := &bin{
+		Name:  "tool",
+		URL:   server.URL + "/owner/tool",
+		asset: "tool_1.0.1_linux_amd64.tar.gz",
+	}
+
+	latest, err := provider.latestVersion(t.Context(), tool)
+	assert.NilError(t, err)
+	assert.Equal(t, latest, "1.0.1")
+
+	tool.Version = latest
+	downloadURL, err := provider.downloadURL(t.Context(), tool)
+	assert.NilError(t, err)
+	assert.Equal(t, downloadURL, server.URL+"/owner/tool/releases/download/v1.0.1/tool_1.0.1_linux_amd64.tar.gz")
+
+	resp, err := server.Client().Get(downloadURL)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+}
+
+func TestGiteaReleasePagination(t *testing.T) {
+	var requests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?draft=false&limit=50&page=%d>; rel="next"`, server.URL, r.URL.Path, max(page, 1)+1))
+		releases := []giteaRelease{{TagName: "nightly"}, {TagName: "v2.0.0-rc.1", Prerelease: true}}
+		if r.URL.Path == "/api/v1/repos/owner/tool/releases" && page == 2 {
+			releases = []giteaRelease{{TagName: "v1.9.1"}, {TagName: "v1.10.0"}}
+		}
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+
+	t.Run("Follows the Link header until a version is found", func(t *testing.T) {
+		requests = 0
+		provider, err := newGiteaProvider(server.Client(), server.URL+"/owner/tool")
+		assert.NilError(t, err)
+		latest, err := provider.latestVersion(t.Context(), &bin{Name: "tool"})
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.10.0")
+		assert.Equal(t, requests, 2)
+	})
+
+	t.Run("Stops after the default number of pages", func(t *testing.T) {
+		requests = 0
+		provider, err := newGiteaProvider(server.Client(), server.URL+"/owner/nightly")
+		assert.NilError(t, err)
+		_, err = provider.latestVersion(t.Context(), &bin{Name: "nightly"})
+		assert.Error(t, err, "no valid non-prerelease semver tags found in Gitea releases matching tag pattern")
+		assert.Equal(t, requests, defaultReleasePages)
+	})
+}
+
+func TestLoadProviderGitea(t *testing.T) {
+	t.Run("Detects codeberg.org", func(t *testing.T) {
+		b := &bin{Name: "tool", URL: "https://codeberg.org/owner/tool"}
+		assert.NilError(t, b.loadProvider(providerOptions{}))
+
+		provider, ok := b.provider.(*giteaProvider)
+		assert.Assert(t, ok)
+		assert.Equal(t, provider.apiURL, "https://codeberg.org/api/v1")
+		assert.Equal(t, provider.owner, "owner")
+		assert.Equal(t, provider.repo, "tool")
+	})
+
+	t.Run("Uses the provider field for self-hosted instances", func(t *testing.T) {
+		b := &bin{Name: "tool", URL: "https://forgejo.example.com/owner/tool", Provider: "gitea"}
+		assert.NilError(t, b.loadProvider(providerOptions{}))
+
+		provider, ok := b.provider.(*giteaProvider)
+		assert.Assert(t, ok)
+		assert.Equal(t, provider.apiURL, "https://forgejo.example.com/api/v1")
+	})
+
+	t.Run("Requires owner and repository", func(t *testing.T) {
+		b := &bin{Name: "tool", URL: "https://forgejo.example.com/tool", Provider: "gitea"}
+		assert.Error(t, b.loadProvider(providerOptions{}), `could not extract owner/repo from "https://forgejo.example.com/tool"`)
+	})
+}
@@ -0,0 +1,165 @@
+package bine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitlabProvider downloads release assets published on gitlab.com or on
+// self-hosted GitLab instances. Unlike GitHub, GitLab releases link to assets
+// that may be hosted anywhere, so the download URL is resolved through the
+// Releases API.
+type gitlabProvider struct {
+	client *http.Client
+	token  string
+
+	// host of the GitLab instance, e.g. "gitlab.com".
+	host string
+	// apiURL is the base URL of the REST API, e.g. "https://gitlab.com/api/v4".
+	apiURL string
+	// project is the URL-encoded path of the project, e.g. "group%2Fproject".
+	project string
+}
+
+var (
+	_ binProvider   = &gitlabProvider{}
+	_ authenticator = &gitlabProvider{}
+)
+
+func newGitLabProvider(client *http.Client, token, projectURL string) (*gitlabProvider, error) {
+	u, err := url.Parse(projectURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %v", err)
+	}
+
+	path := strings.Trim(u.Path, "/")
+	if u.Host == "" || !strings.Contains(path, "/") {
+		return nil, fmt.Errorf("could not extract GitLab project from %q", projectURL)
+	}
+
+	return &gitlabProvider{
+		client:  client,
+		token:   token,
+		host:    u.Host,
+		apiURL:  fmt.Sprintf("%s://%s/api/v4", u.Scheme, u.Host),
+		project: url.PathEscape(path),
+	}, nil
+}
+
+type gitlabRelease struct {
+	TagName         string `json:"tag_name"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []gitlabReleaseLink `json:"links"`
+	} `json:"assets"`
+}
+
+type gitlabReleaseLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+func (p *gitlabProvider) downloadURL(ctx context.Context, b *bin) (string, error) {
+	var release gitlabRelease
+	path := fmt.Sprintf("/projects/%s/releases/%s", p.project, url.PathEscape(b.tag()))
+	if err := p.get(ctx, path, &release); err != nil {
+		return "", err
+	}
+
+	for _, link := range release.Assets.Links {
+		if link.Name != b.asset {
+			continue
+		}
+		if link.DirectAssetURL != "" {
+			return link.DirectAssetURL, nil
+		}
+		return link.URL, nil
+	}
+
+	return "", fmt.Errorf("asset %q not found in GitLab release %q", b.asset, b.tag())
+}
+
+// latestVersion reads the releases, newest first, until a page has a version
+// considered by the bin, up to defaultReleasePages pages.
+func (p *gitlabProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
+	var tags []string
+	next := fmt.Sprintf("%s/projects/%s/releases?per_page=100", p.apiURL, p.project)
+	for page := 0; next != "" && page < defaultReleasePages; page++ {
+		var releases []gitlabRelease
+		link, err := p.fetch(ctx, next, &releases)
+		if err != nil {
+			return "", err
+		}
+
+		// Upcoming releases are scheduled for the future and not available
+		// yet. GitLab has no prerelease flag, so latestTaggedVersion relies
+		// on the semver prerelease suffix.
+		for _, release := range releases {
+			if release.UpcomingRelease {
+				continue
+			}
+			tags = append(tags, release.TagName)
+		}
+
+		if latestTaggedVersion(bin, tags) != "" {
+			break
+		}
+		if next, err = nextPage(next, link); err != nil {
+			return "", err
+		}
+	}
+
+	latestVersion := latestTaggedVersion(bin, tags)
+	if latestVersion == "" {
+		return "", fmt.Errorf("no valid %s tags found in GitLab releases matching tag pattern", bin.versionKind())
+	}
+
+	return latestVersion, nil
+}
+
+// authenticate sends the token along with downloads from the GitLab instance,
+// e.g. the package registry of a private project. Links to other hosts are
+// left alone so the token doesn't leak.
+func (p *gitlabProvider) authenticate(req *http.Request) {
+	if p.token != "" && req.URL.Host == p.host {
+		req.Header.Set("PRIVATE-TOKEN", p.token)
+	}
+}
+
+// get sends a request to the GitLab API and decodes the JSON response in v.
+func (p *gitlabProvider) get(ctx context.Context, path string, v any) error {
+	_, err := p.fetch(ctx, p.apiURL+path, v)
+	return err
+}
+
+// fetch is like get but takes the full URL of the request, and returns the
+// Link header of the response used for pagination.
+func (p *gitlabProvider) fetch(ctx context.Context, url string, v any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	p.authenticate(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitLab API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode GitLab API response: %v", err)
+	}
+
+	return resp.Header.Get("Link"), nil
+}
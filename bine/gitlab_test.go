@@ -0,0 +1,147 @@
+package bine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGitLabProvider(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("PRIVATE-TOKEN"))
+		switch r.URL.RawPath {
+		case "/api/v4/projects/group%2Fsub%2Ftool/releases":
+			releases := []gitlabRelease{
+				{TagName: "v2.0.0", UpcomingRelease: true},
+				{TagName: "v1.1.0-rc.1"},
+				{TagName: "v1.0.1"},
+				{TagName: "v1.0.0"},
+			}
+			_ = json.NewEncoder(w).Encode(releases)
+		case "/api/v4/projects/group%2Fsub%2Ftool/releases/v1.0.0":
+			var release gitlabRelease
+			release.TagName = "v1.0.0"
+			release.Assets.Links = []gitlabReleaseLink{
+				{Name: "tool_1.0.0_linux_amd64.tar.gz", URL: "https://example.com/other", DirectAssetURL: "https://gitlab.example.com/group/sub/tool/-/releases/v1.0.0/downloads/tool_1.0.0_linux_amd64.tar.gz"},
+				{Name: "checksums.txt", URL: "https://cdn.example.com/checksums.txt"},
+			}
+			_ = json.NewEncoder(w).Encode(release)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := newGitLabProvider(server.Client(), "test-token", server.URL+"/group/sub/tool")
+	assert.NilError(t, err)
+
+	tool := &bin{
+		Name:    "tool",
+		Version: "1.0.0",
+		URL:     server.URL + "/group/sub/tool",
+		asset:   "tool_1.0.0_linux_amd64.tar.gz",
+	}
+
+	t.Run("downloadURL prefers the direct asset URL", func(t *testing.T) {
+		downloadURL, err := provider.downloadURL(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, downloadURL, "https://gitlab.example.com/group/sub/tool/-/releases/v1.0.0/downloads/tool_1.0.0_linux_amd64.tar.gz")
+	})
+
+	t.Run("downloadURL falls back to the link URL", func(t *testing.T) {
+		sidecar := *tool
+		sidecar.asset = "checksums.txt"
+
+		downloadURL, err := provider.downloadURL(t.Context(), &sidecar)
+		assert.NilError(t, err)
+		assert.Equal(t, downloadURL, "https://cdn.example.com/checksums.txt")
+	})
+
+	t.Run("downloadURL fails when the asset is not linked", func(t *testing.T) {
+		missing := *tool
+		missing.asset = "tool_1.0.0_plan9_amd64.tar.gz"
+
+		_, err := provider.downloadURL(t.Context(), &missing)
+		assert.Error(t, err, `asset "tool_1.0.0_plan9_amd64.tar.gz" not found in GitLab release "v1.0.0"`)
+	})
+
+	t.Run("latestVersion skips upcoming releases and prereleases", func(t *testing.T) {
+		latest, err := provider.latestVersion(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.0.1")
+	})
+
+	t.Run("Sends the token to the GitLab host only", func(t *testing.T) {
+		for _, token := range tokens {
+			assert.Equal(t, token, "test-token")
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "https://cdn.example.com/checksums.txt", nil)
+		assert.NilError(t, err)
+		provider.authenticate(req)
+		assert.Equal(t, req.Header.Get("PRIVATE-TOKEN"), "")
+	})
+}
+
+func TestGitLabReleasePagination(t *testing.T) {
+	var requests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=%d>; rel="next"`, server.URL, r.URL.EscapedPath(), max(page, 1)+1))
+		releases := []gitlabRelease{{TagName: "nightly"}}
+		if r.URL.EscapedPath() == "/api/v4/projects/example%2Ftool/releases" && page == 2 {
+			releases = []gitlabRelease{{TagName: "v1.9.1"}, {TagName: "v1.10.0"}}
+		}
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+
+	t.Run("Follows the Link header until a version is found", func(t *testing.T) {
+		requests = 0
+		provider, err := newGitLabProvider(server.Client(), "", server.URL+"/example/tool")
+		assert.NilError(t, err)
+		latest, err := provider.latestVersion(t.Context(), &bin{Name: "tool"})
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.10.0")
+		assert.Equal(t, requests, 2)
+	})
+
+	t.Run("Stops after the default number of pages", func(t *testing.T) {
+		requests = 0
+		provider, err := newGitLabProvider(server.Client(), "", server.URL+"/example/nightly")
+		assert.NilError(t, err)
+		_, err = provider.latestVersion(t.Context(), &bin{Name: "nightly"})
+		assert.Error(t, err, "no valid non-prerelease semver tags found in GitLab releases matching tag pattern")
+		assert.Equal(t, requests, defaultReleasePages)
+	})
+}
+
+func TestLoadProviderGitLab(t *testing.T) {
+	t.Run("Detects gitlab.com", func(t *testing.T) {
+		b := &bin{Name: "tool", URL: "https://gitlab.com/group/tool"}
+		assert.NilError(t, b.loadProvider(providerOptions{glAPIToken: "token"}))
+
+		provider, ok := b.provider.(*gitlabProvider)
+		assert.Assert(t, ok)
+		assert.Equal(t, provider.apiURL, "https://gitlab.com/api/v4")
+		assert.Equal(t, provider.project, "group%2Ftool")
+		assert.Equal(t, provider.token, "token")
+	})
+
+	t.Run("Uses the provider field for self-hosted instances", func(t *testing.T) {
+		b := &bin{Name: "tool", URL: "https://git.example.com/group/tool", Provider: "gitlab"}
+		assert.NilError(t, b.loadProvider(providerOptions{}))
+
+		provider, ok := b.provider.(*gitlabProvider)
+		assert.Assert(t, ok)
+		assert.Equal(t, provider.apiURL, "https://git.example.com/api/v4")
+	})
+}
@@ -0,0 +1,148 @@
+package bine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goModBin is the configuration entry of a bin imported from a go.mod tool
+// directive.
+type goModBin struct {
+	Name      string `json:"name" toml:"name"`
+	GoPackage string `json:"go_package" toml:"go_package"`
+	Version   string `json:"version,omitempty" toml:"version,omitempty"`
+	GoMod     bool   `json:"go_mod,omitempty" toml:"go_mod,omitempty"`
+}
+
+// goModPath returns the path of the go.mod file found next to the
+// configuration file.
+func goModPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "go.mod")
+}
+
+func readGoMod(path string) (*modfile.File, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read go.mod: %v", err)
+	}
+	f, err := modfile.Parse(path, blob, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse go.mod: %v", err)
+	}
+
+	return f, nil
+}
+
+// goModRequirement returns the module required by f that provides pkg and its
+// version, which is what "go tool" would run.
+func goModRequirement(f *modfile.File, pkg string) (string, string, error) {
+	provides := func(mod string) bool {
+		return pkg == mod || strings.HasPrefix(pkg, mod+"/")
+	}
+
+	if f.Module != nil && provides(f.Module.Mod.Path) {
+		return "", "", fmt.Errorf("package %q is provided by the main module", pkg)
+	}
+
+	var req *modfile.Require
+	for _, r := range f.Require {
+		if provides(r.Mod.Path) && (req == nil || len(r.Mod.Path) > len(req.Mod.Path)) {
+			req = r
+		}
+	}
+	if req == nil {
+		return "", "", fmt.Errorf("no module required by go.mod provides package %q", pkg)
+	}
+
+	// "go install pkg@version" ignores the replace directives of go.mod, so
+	// it would install something else.
+	for _, r := range f.Replace {
+		if r.Old.Path == req.Mod.Path && (r.Old.Version == "" || r.Old.Version == req.Mod.Version) {
+			return "", "", fmt.Errorf("module %s is replaced in go.mod, which go install doesn't support", req.Mod.Path)
+		}
+	}
+
+	return req.Mod.Path, req.Mod.Version, nil
+}
+
+// resolveGoModVersions sets the version of the go_mod bins to the version of
+// their module required by the go.mod file next to the configuration file.
+func resolveGoModVersions(configPath string, bins []*bin) error {
+	var f *modfile.File
+	for _, b := range bins {
+		if !b.GoMod {
+			continue
+		}
+		if b.GoPackage == "" {
+			return fmt.Errorf("bin %q: go_mod requires go_package", b.Name)
+		}
+		if b.Version != "" {
+			return fmt.Errorf("bin %q: version cannot be used with go_mod", b.Name)
+		}
+
+		if f == nil {
+			var err error
+			if f, err = readGoMod(goModPath(configPath)); err != nil {
+				return err
+			}
+		}
+		_, version, err := goModRequirement(f, b.GoPackage)
+		if err != nil {
+			return fmt.Errorf("bin %q: %v", b.Name, err)
+		}
+		b.Version = strings.TrimPrefix(version, "v")
+	}
+
+	return nil
+}
+
+// goModTools returns the configuration entries of the tool directives of the
+// go.mod file that aren't configured yet, either tracking go.mod or pinned to
+// the version it requires. Tools provided by the main module are skipped,
+// they are built with "go tool".
+func goModTools(f *modfile.File, bins []*bin, pin bool) ([]goModBin, error) {
+	names := map[string]string{}
+	packages := map[string]bool{}
+	for _, b := range bins {
+		names[b.Name] = b.GoPackage
+		packages[b.GoPackage] = true
+	}
+
+	var tools []goModBin
+	for _, tool := range f.Tool {
+		if packages[tool.Path] {
+			continue
+		}
+		if f.Module != nil && (tool.Path == f.Module.Mod.Path || strings.HasPrefix(tool.Path, f.Module.Mod.Path+"/")) {
+			continue
+		}
+
+		_, version, err := goModRequirement(f, tool.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := goModBin{
+			Name:      defaultGoBinaryName(tool.Path),
+			GoPackage: tool.Path,
+		}
+		if pkg, ok := names[entry.Name]; ok {
+			return nil, fmt.Errorf("cannot import %q, bin %q already exists for %q", tool.Path, entry.Name, pkg)
+		}
+		if pin {
+			entry.Version = strings.TrimPrefix(version, "v")
+		} else {
+			entry.GoMod = true
+		}
+
+		names[entry.Name] = tool.Path
+		packages[tool.Path] = true
+		tools = append(tools, entry)
+	}
+
+	return tools, nil
+}
@@ -0,0 +1,209 @@
+package bine
+
+import (
+	"os"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+const testGoMod = `module example.com/project
+
+go 1.24
+
+tool (
+	example.com/project/cmd/gen
+	github.com/golang-migrate/migrate/v4/cmd/migrate
+	github.com/sqlc-dev/sqlc/cmd/sqlc
+)
+
+require (
+	github.com/golang-migrate/migrate/v4 v4.18.1
+	github.com/sqlc-dev/sqlc v1.28.0
+	golang.org/x/tools v0.30.0
+)
+
+replace golang.org/x/tools => ../tools
+`
+
+func TestGoModRequirement(t *testing.T) {
+	f, err := modfile.Parse("go.mod", []byte(testGoMod+`
+require github.com/golang-migrate/migrate/v4/cmd/migrate v0.0.1
+`), nil)
+	assert.NilError(t, err)
+
+	mod, version, err := goModRequirement(f, "github.com/sqlc-dev/sqlc/cmd/sqlc")
+	assert.NilError(t, err)
+	assert.Equal(t, mod, "github.com/sqlc-dev/sqlc")
+	assert.Equal(t, version, "v1.28.0")
+
+	// The longest module path wins.
+	mod, version, err = goModRequirement(f, "github.com/golang-migrate/migrate/v4/cmd/migrate")
+	assert.NilError(t, err)
+	assert.Equal(t, mod, "github.com/golang-migrate/migrate/v4/cmd/migrate")
+	assert.Equal(t, version, "v0.0.1")
+
+	_, _, err = goModRequirement(f, "example.com/project/cmd/gen")
+	assert.Error(t, err, `package "example.com/project/cmd/gen" is provided by the main module`)
+
+	_, _, err = goModRequirement(f, "github.com/sqlc-dev/sqlcx")
+	assert.Error(t, err, `no module required by go.mod provides package "github.com/sqlc-dev/sqlcx"`)
+
+	_, _, err = goModRequirement(f, "golang.org/x/tools/cmd/stringer")
+	assert.Error(t, err, "module golang.org/x/tools is replaced in go.mod, which go install doesn't support")
+}
+
+func TestLoadConfigGoMod(t *testing.T) {
+	t.Run("Uses the version required by go.mod", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine",
+			fs.WithFile("go.mod", testGoMod),
+			fs.WithFile(".bine.json", `{
+				"project": "test",
+				"bins": [{"name": "sqlc", "go_package": "github.com/sqlc-dev/sqlc/cmd/sqlc", "go_mod": true}]
+			}`),
+		)
+		t.Chdir(tmpDir.Path())
+
+		cfg, err := loadConfig(t.Context(), providerOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, cfg.Bins[0].Version, "1.28.0")
+		assert.Equal(t, cfg.Bins[0].isLatest(), false)
+	})
+
+	t.Run("Rejects go_mod bins with a version", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine",
+			fs.WithFile("go.mod", testGoMod),
+			fs.WithFile(".bine.json", `{
+				"project": "test",
+				"bins": [{"name": "sqlc", "go_package": "github.com/sqlc-dev/sqlc/cmd/sqlc", "version": "1.0.0", "go_mod": true}]
+			}`),
+		)
+		t.Chdir(tmpDir.Path())
+
+		_, err := loadConfig(t.Context(), providerOptions{})
+		assert.Error(t, err, `bin "sqlc": version cannot be used with go_mod`)
+	})
+
+	t.Run("Fails without go.mod", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine",
+			fs.WithFile(".bine.json", `{
+				"project": "test",
+				"bins": [{"name": "sqlc", "go_package": "github.com/sqlc-dev/sqlc/cmd/sqlc", "go_mod": true}]
+			}`),
+		)
+		t.Chdir(tmpDir.Path())
+
+		_, err := loadConfig(t.Context(), providerOptions{})
+		assert.ErrorContains(t, err, "read go.mod")
+	})
+}
+
+func TestImportGoMod(t *testing.T) {
+	t.Run("Appends to JSON configs", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine",
+			fs.WithFile("go.mod", testGoMod),
+			fs.WithFile(".bine.json", `{
+  // Tools of the project.
+  "project": "test",
+  "bins": [
+    {
+      "name": "sqlc", // Already configured.
+      "go_package": "github.com/sqlc-dev/sqlc/cmd/sqlc",
+      "version": "1.27.0"
+    }
+  ]
+}
+`),
+		)
+		t.Chdir(tmpDir.Path())
+
+		b, err := NewWithOptions(WithCacheDir(t.TempDir()))
+		assert.NilError(t, err)
+
+		names, err := b.ImportGoMod("", false)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, names, []string{"migrate"})
+
+		blob, err := os.ReadFile(tmpDir.Join(".bine.json"))
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), `{
+  // Tools of the project.
+  "project": "test",
+  "bins": [
+    {
+      "name": "sqlc", // Already configured.
+      "go_package": "github.com/sqlc-dev/sqlc/cmd/sqlc",
+      "version": "1.27.0"
+    },
+    {
+      "name": "migrate",
+      "go_package": "github.com/golang-migrate/migrate/v4/cmd/migrate",
+      "go_mod": true
+    }
+  ]
+}
+`)
+
+		migrate, err := b.load("migrate")
+		assert.NilError(t, err)
+		assert.Equal(t, migrate.Version, "4.18.1")
+
+		// Importing again adds nothing.
+		b, err = NewWithOptions(WithCacheDir(t.TempDir()))
+		assert.NilError(t, err)
+		names, err = b.ImportGoMod("", false)
+		assert.NilError(t, err)
+		assert.Equal(t, len(names), 0)
+	})
+
+	t.Run("Appends pinned versions to TOML configs", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine",
+			fs.WithFile("go.mod", testGoMod),
+			fs.WithFile(".bine.toml", `# Tools of the project.
+project = "test"
+`),
+		)
+		t.Chdir(tmpDir.Path())
+
+		b, err := NewWithOptions(WithCacheDir(t.TempDir()))
+		assert.NilError(t, err)
+
+		names, err := b.ImportGoMod("", true)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, names, []string{"migrate", "sqlc"})
+
+		blob, err := os.ReadFile(tmpDir.Join(".bine.toml"))
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), `# Tools of the project.
+project = "test"
+
+[[bins]]
+name = 'migrate'
+go_package = 'github.com/golang-migrate/migrate/v4/cmd/migrate'
+version = '4.18.1'
+
+[[bins]]
+name = 'sqlc'
+go_package = 'github.com/sqlc-dev/sqlc/cmd/sqlc'
+version = '1.28.0'
+`)
+	})
+
+	t.Run("Rejects TOML configs with inline bins", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "bine",
+			fs.WithFile("go.mod", testGoMod),
+			fs.WithFile(".bine.toml", `project = "test"
+bins = []
+`),
+		)
+		t.Chdir(tmpDir.Path())
+
+		b, err := NewWithOptions(WithCacheDir(t.TempDir()))
+		assert.NilError(t, err)
+
+		_, err = b.ImportGoMod("", false)
+		assert.Error(t, err, "adding bins is only supported for TOML configs using [[bins]] tables")
+	})
+}
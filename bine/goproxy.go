@@ -0,0 +1,306 @@
+package bine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// errModuleNotFound is returned when a module proxy doesn't know about a
+// module, which lets the lookup fall back to the next proxy in the list.
+var errModuleNotFound = errors.New("module not found")
+
+// goProxyEntry is an element of the GOPROXY list.
+type goProxyEntry struct {
+	// url of the proxy, or "direct" or "off".
+	url string
+	// fallbackOnError is true when the entry is followed by a pipe, meaning
+	// that any error falls back to the next entry. With a comma, only
+	// "not found" errors do.
+	fallbackOnError bool
+}
+
+// parseGoProxy parses the value of GOPROXY.
+func parseGoProxy(value string) []goProxyEntry {
+	var entries []goProxyEntry
+	for value != "" {
+		i := strings.IndexAny(value, ",|")
+		var entry goProxyEntry
+		if i < 0 {
+			entry.url, value = value, ""
+		} else {
+			entry.url, entry.fallbackOnError, value = value[:i], value[i] == '|', value[i+1:]
+		}
+		entry.url = strings.TrimSpace(entry.url)
+		if entry.url == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// goEnv returns the values of the Go environment variables, preferring the
+// process environment and falling back to "go env" for the rest, e.g. for
+// values set with "go env -w".
+func goEnv(ctx context.Context, keys ...string) map[string]string {
+	env := map[string]string{}
+	var missing []string
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			env[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return env
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return env
+	}
+	out, err := execCommand(ctx, goBin, append([]string{"env", "-json"}, missing...)...).Output()
+	if err != nil {
+		return env
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal(out, &values); err != nil {
+		return env
+	}
+	for _, key := range missing {
+		env[key] = values[key]
+	}
+
+	return env
+}
+
+// goModuleProxy looks up module versions the way the go command does, using
+// the proxies listed in GOPROXY and skipping them for the modules matched by
+// GONOPROXY or GOPRIVATE.
+type goModuleProxy struct {
+	client  *http.Client
+	proxies []goProxyEntry
+	noProxy string
+}
+
+func newGoModuleProxy(ctx context.Context, client *http.Client) *goModuleProxy {
+	env := goEnv(ctx, "GOPROXY", "GOPRIVATE", "GONOPROXY")
+
+	goproxy := env["GOPROXY"]
+	if goproxy == "" {
+		goproxy = "https://proxy.golang.org,direct"
+	}
+	noProxy := env["GONOPROXY"]
+	if noProxy == "" {
+		noProxy = env["GOPRIVATE"]
+	}
+
+	return &goModuleProxy{
+		client:  client,
+		proxies: parseGoProxy(goproxy),
+		noProxy: noProxy,
+	}
+}
+
+// latestVersion finds the module providing the package of the binary, walking
+// up its path, and returns the latest version of the module without the "v"
+// prefix. Prereleases newer than the latest release win when the binary
+// follows the prerelease channel, and versions out of its range are skipped.
+func (p *goModuleProxy) latestVersion(ctx context.Context, b *bin) (string, error) {
+	pkgPath := b.GoPackage
+	var lastErr error
+	for modPath := pkgPath; modPath != "." && modPath != "/"; modPath = path.Dir(modPath) {
+		version, err := p.moduleLatestVersion(ctx, modPath, b)
+		if errors.Is(err, errModuleNotFound) {
+			lastErr = err
+			continue
+		} else if err != nil {
+			return "", fmt.Errorf("lookup module %q: %v", modPath, err)
+		}
+		return strings.TrimPrefix(version, "v"), nil
+	}
+
+	return "", fmt.Errorf("cannot find module providing package %q: %v", pkgPath, lastErr)
+}
+
+// moduleLatestVersion queries the proxies in order until one of them knows
+// about the module.
+func (p *goModuleProxy) moduleLatestVersion(ctx context.Context, modPath string, b *bin) (string, error) {
+	proxies := p.proxies
+	if module.MatchPrefixPatterns(p.noProxy, modPath) {
+		proxies = []goProxyEntry{{url: "direct"}}
+	}
+
+	err := errors.New("GOPROXY list is empty")
+	for _, proxy := range proxies {
+		var version string
+		switch proxy.url {
+		case "off":
+			return "", errors.New("module lookup disabled by GOPROXY=off")
+		case "direct":
+			version, err = goListLatest(ctx, modPath, b)
+		default:
+			version, err = p.proxyLatest(ctx, proxy.url, modPath, b)
+		}
+		if err == nil {
+			return version, nil
+		}
+		if !proxy.fallbackOnError && !errors.Is(err, errModuleNotFound) {
+			return "", err
+		}
+	}
+
+	return "", err
+}
+
+// proxyLatest returns the latest version of the module known to the proxy.
+// Like the go command, it prefers the highest release, then the highest
+// prerelease, and falls back to the @latest endpoint, e.g. for modules
+// without tags. On the prerelease channel, the highest version wins.
+func (p *goModuleProxy) proxyLatest(ctx context.Context, proxyURL, modPath string, b *bin) (string, error) {
+	escaped, err := module.EscapePath(modPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errModuleNotFound, err)
+	}
+	root := strings.TrimSuffix(proxyURL, "/") + "/" + escaped
+
+	body, err := p.get(ctx, root+"/@v/list")
+	if err != nil {
+		return "", err
+	}
+
+	var release, prerelease string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		version, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !semver.IsValid(version) || b.isRange() && !b.constraint.check(version) {
+			continue
+		}
+		if semver.Prerelease(version) == "" {
+			if release == "" || semver.Compare(version, release) > 0 {
+				release = version
+			}
+		} else if prerelease == "" || semver.Compare(version, prerelease) > 0 {
+			prerelease = version
+		}
+	}
+	if b.prereleases() && prerelease != "" && semver.Compare(prerelease, release) > 0 {
+		return prerelease, nil
+	} else if release != "" {
+		return release, nil
+	} else if prerelease != "" {
+		return prerelease, nil
+	} else if b.isRange() {
+		return "", fmt.Errorf("no version of module %s matches %s", modPath, b.Version)
+	}
+
+	body, err = p.get(ctx, root+"/@latest")
+	if err != nil {
+		return "", err
+	}
+	var info struct{ Version string }
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("decode @latest response: %v", err)
+	}
+	if !semver.IsValid(info.Version) {
+		return "", fmt.Errorf("invalid version %q in @latest response", info.Version)
+	}
+
+	return info.Version, nil
+}
+
+func (p *goModuleProxy) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Proxies report unknown modules and versions with these codes.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("%w: %s returned status %d", errModuleNotFound, url, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("read body: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// goListLatest asks the go command to resolve the latest version of the module
+// from its origin, e.g. for private modules. It honors the credentials and the
+// VCS configuration of the user. On the prerelease channel or with a version
+// range, the highest of the known versions considered by the binary wins.
+func goListLatest(ctx context.Context, modPath string, b *bin) (string, error) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return "", fmt.Errorf("cannot find 'go' command: %v", err)
+	}
+
+	args := []string{"list", "-m", "-json"}
+	if b.prereleases() || b.isRange() {
+		args = append(args, "-versions")
+	}
+	cmd := execCommand(ctx, goBin, append(args, modPath+"@latest")...)
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, "GOPROXY=direct")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// The go command doesn't tell apart unknown modules from other failures,
+	// so let the caller keep looking for the module root.
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: go list -m %s@latest: %v: %s", errModuleNotFound, modPath, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var info struct {
+		Version  string
+		Versions []string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		return "", fmt.Errorf("decode go list output: %v", err)
+	}
+	if !semver.IsValid(info.Version) {
+		return "", fmt.Errorf("invalid version %q reported by go list", info.Version)
+	}
+	if b.isRange() {
+		info.Version = ""
+	}
+	for _, version := range info.Versions {
+		if b.considers(version) && (info.Version == "" || semver.Compare(version, info.Version) > 0) {
+			info.Version = version
+		}
+	}
+	if info.Version == "" {
+		return "", fmt.Errorf("no version of module %s matches %s", modPath, b.Version)
+	}
+
+	return info.Version, nil
+}
@@ -0,0 +1,220 @@
+package bine
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+// TestHelperProcessGoListLatest handles the "go list -m -json <module>@latest"
+// command in tests, listing the known versions with -versions. Only
+// example.com/private/tool is a module.
+func TestHelperProcessGoListLatest(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args
+	if args[len(args)-1] == "example.com/private/tool@latest" && os.Getenv("GOPROXY") == "direct" {
+		if slices.Contains(args, "-versions") {
+			fmt.Println(`{"Path": "example.com/private/tool", "Version": "v1.4.0", "Versions": ["v1.3.0", "v1.4.0", "v1.5.0-rc.1"]}`)
+		} else {
+			fmt.Println(`{"Path": "example.com/private/tool", "Version": "v1.4.0"}`)
+		}
+		os.Exit(0)
+	}
+
+	fmt.Fprintln(os.Stderr, "not a module")
+	os.Exit(1)
+}
+
+func newGoProxyServer(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken/example.com/tool/@v/list" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGoModuleProxy(t *testing.T) {
+	server := newGoProxyServer(t, map[string]string{
+		"/example.com/goa/v3/@v/list":        "v3.0.0\nv3.1.0\nv3.2.0-rc.1\n",
+		"/github.com/!foo/bar/@v/list":       "v0.1.0\nv0.2.0-beta.1\n",
+		"/example.com/untagged/@v/list":      "",
+		"/example.com/untagged/@latest":      `{"Version": "v0.0.0-20250101000000-abcdef123456"}`,
+		"/example.com/prerelease/@v/list":    "v1.0.0-alpha.1\nv1.0.0-beta.1\n",
+		"/example.com/released/@v/list":      "v1.0.0-rc.1\nv1.0.0\nv1.0.0-rc.2\n",
+		"/second/example.com/tool/@v/list":   "v2.0.0\n",
+		"/second/example.com/goa/v3/@v/list": "v3.9.0\n",
+	})
+
+	tests := []struct {
+		name    string
+		goproxy string
+		private string
+		pkg     string
+		channel string
+		version string
+		want    string
+		err     string
+	}{
+		{
+			name:    "Walks up to the module root",
+			goproxy: server.URL,
+			pkg:     "example.com/goa/v3/cmd/goa",
+			want:    "3.1.0",
+		},
+		{
+			name:    "Escapes the module path",
+			goproxy: server.URL,
+			pkg:     "github.com/Foo/bar/cmd/bar",
+			want:    "0.1.0",
+		},
+		{
+			name:    "Falls back to @latest when there are no tags",
+			goproxy: server.URL,
+			pkg:     "example.com/untagged",
+			want:    "0.0.0-20250101000000-abcdef123456",
+		},
+		{
+			name:    "Uses prereleases when there are no releases",
+			goproxy: server.URL,
+			pkg:     "example.com/prerelease",
+			want:    "1.0.0-beta.1",
+		},
+		{
+			name:    "Uses newer prereleases on the prerelease channel",
+			goproxy: server.URL,
+			pkg:     "example.com/goa/v3/cmd/goa",
+			channel: channelPrerelease,
+			want:    "3.2.0-rc.1",
+		},
+		{
+			name:    "Orders releases after their prereleases on the prerelease channel",
+			goproxy: server.URL,
+			pkg:     "example.com/released",
+			channel: channelPrerelease,
+			want:    "1.0.0",
+		},
+		{
+			name:    "Uses the latest version in range",
+			goproxy: server.URL,
+			pkg:     "example.com/goa/v3/cmd/goa",
+			version: "~3.0",
+			want:    "3.0.0",
+		},
+		{
+			name:    "Fails when no version is in range",
+			goproxy: server.URL,
+			pkg:     "example.com/goa/v3/cmd/goa",
+			version: "^3.5",
+			err:     `lookup module "example.com/goa/v3": no version of module example.com/goa/v3 matches ^3.5`,
+		},
+		{
+			name:    "Falls back to the next proxy when the module is not found",
+			goproxy: server.URL + "," + server.URL + "/second",
+			pkg:     "example.com/tool/cmd/tool",
+			want:    "2.0.0",
+		},
+		{
+			name:    "Uses the first proxy that knows about the module",
+			goproxy: server.URL + "," + server.URL + "/second",
+			pkg:     "example.com/goa/v3/cmd/goa",
+			want:    "3.1.0",
+		},
+		{
+			name:    "Stops at errors after a comma",
+			goproxy: server.URL + "/broken," + server.URL + "/second",
+			pkg:     "example.com/tool",
+			err:     fmt.Sprintf(`lookup module "example.com/tool": %s/broken/example.com/tool/@v/list returned status 500`, server.URL),
+		},
+		{
+			name:    "Falls back on errors after a pipe",
+			goproxy: server.URL + "/broken|" + server.URL + "/second",
+			pkg:     "example.com/tool",
+			want:    "2.0.0",
+		},
+		{
+			name:    "Honors off",
+			goproxy: "off",
+			pkg:     "example.com/tool",
+			err:     `lookup module "example.com/tool": module lookup disabled by GOPROXY=off`,
+		},
+		{
+			name:    "Uses the go command for direct",
+			goproxy: "direct",
+			pkg:     "example.com/private/tool/cmd/tool",
+			want:    "1.4.0",
+		},
+		{
+			name:    "Uses the known versions of the go command on the prerelease channel",
+			goproxy: "direct",
+			pkg:     "example.com/private/tool/cmd/tool",
+			channel: channelPrerelease,
+			want:    "1.5.0-rc.1",
+		},
+		{
+			name:    "Skips the proxies for private modules",
+			goproxy: server.URL,
+			private: "example.com/private",
+			pkg:     "example.com/private/tool/cmd/tool",
+			want:    "1.4.0",
+		},
+		{
+			name:    "Fails when no module provides the package",
+			goproxy: server.URL,
+			pkg:     "example.com/missing/cmd/missing",
+			err:     fmt.Sprintf(`cannot find module providing package "example.com/missing/cmd/missing": module not found: %s/example.com/@v/list returned status 404`, server.URL),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			injectFakeExec(t, "TestHelperProcessGoListLatest")
+			t.Setenv("GOPROXY", tc.goproxy)
+			t.Setenv("GOPRIVATE", tc.private)
+			t.Setenv("GONOPROXY", "")
+
+			provider := &goProvider{client: server.Client()}
+			tool := &bin{Name: "tool", GoPackage: tc.pkg, Version: tc.version, Channel: tc.channel}
+			if tc.version != "" {
+				var err error
+				tool.constraint, err = parseVersionConstraint(tc.version)
+				assert.NilError(t, err)
+			}
+			version, err := provider.latestVersion(t.Context(), tool)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, version, tc.want)
+		})
+	}
+}
+
+func TestParseGoProxy(t *testing.T) {
+	entries := parseGoProxy("https://a.example.com|https://b.example.com,,direct")
+	assert.DeepEqual(t, entries, []goProxyEntry{
+		{url: "https://a.example.com", fallbackOnError: true},
+		{url: "https://b.example.com"},
+		{url: "direct"},
+	}, cmp.AllowUnexported(goProxyEntry{}))
+}
@@ -0,0 +1,188 @@
+package bine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// githubReleasesPerRepo is the number of releases of each repository fetched
+// with GraphQL, the maximum allowed by the API.
+const githubReleasesPerRepo = 100
+
+// githubReleaseCache holds the latest releases of GitHub repositories, fetched
+// with a single GraphQL query ahead of the latest-version checks of all the
+// GitHub bins. The REST API is used for repositories missing from the cache.
+type githubReleaseCache struct {
+	repos map[string]*githubCachedReleases
+}
+
+type githubCachedReleases struct {
+	releases []githubRelease
+	// complete is set when the repository has no other releases.
+	complete bool
+}
+
+func newGitHubReleaseCache() *githubReleaseCache {
+	return &githubReleaseCache{repos: map[string]*githubCachedReleases{}}
+}
+
+// githubRepoKey identifies a repository of a GitHub instance. Owners and
+// repositories are case-insensitive.
+func githubRepoKey(apiURL, owner, repo string) string {
+	return strings.ToLower(apiURL + "/repos/" + owner + "/" + repo)
+}
+
+func (c *githubReleaseCache) get(apiURL, owner, repo string) (*githubCachedReleases, bool) {
+	if c == nil {
+		return nil, false
+	}
+	cached, ok := c.repos[githubRepoKey(apiURL, owner, repo)]
+	return cached, ok
+}
+
+func (c *githubReleaseCache) reset() {
+	if c != nil {
+		clear(c.repos)
+	}
+}
+
+type githubRepository struct {
+	owner, name string
+}
+
+// fetch queries the latest releases of the repositories with GraphQL and
+// adds them to the cache. Repositories that can't be found are left out.
+func (c *githubReleaseCache) fetch(ctx context.Context, client *http.Client, apiURL, token string, repos []githubRepository) error {
+	var params, fields []string
+	variables := map[string]any{}
+	for i, repo := range repos {
+		params = append(params, fmt.Sprintf("$owner%[1]d: String!, $name%[1]d: String!", i))
+		fields = append(fields, fmt.Sprintf("r%[1]d: repository(owner: $owner%[1]d, name: $name%[1]d) { ...releases }", i))
+		variables[fmt.Sprintf("owner%d", i)] = repo.owner
+		variables[fmt.Sprintf("name%d", i)] = repo.name
+	}
+	query := fmt.Sprintf(`query(%s) { %s }
+fragment releases on Repository {
+	releases(first: %d, orderBy: {field: CREATED_AT, direction: DESC}) {
+		pageInfo { hasNextPage }
+		nodes { tagName isPrerelease isDraft }
+	}
+}`, strings.Join(params, ", "), strings.Join(fields, " "), githubReleasesPerRepo)
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("encode GraphQL query: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, githubGraphQLURL(apiURL), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub GraphQL API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data map[string]*struct {
+			Releases struct {
+				PageInfo struct {
+					HasNextPage bool `json:"hasNextPage"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					TagName      string `json:"tagName"`
+					IsPrerelease bool   `json:"isPrerelease"`
+					IsDraft      bool   `json:"isDraft"`
+				} `json:"nodes"`
+			} `json:"releases"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode GitHub GraphQL API response: %v", err)
+	}
+	if len(result.Data) == 0 && len(result.Errors) > 0 {
+		return errors.New(result.Errors[0].Message)
+	}
+
+	for i, repo := range repos {
+		data := result.Data[fmt.Sprintf("r%d", i)]
+		if data == nil {
+			continue // Not found or not accessible with this token.
+		}
+		cached := &githubCachedReleases{complete: !data.Releases.PageInfo.HasNextPage}
+		for _, node := range data.Releases.Nodes {
+			if node.IsDraft {
+				continue
+			}
+			cached.releases = append(cached.releases, githubRelease{TagName: node.TagName, Prerelease: node.IsPrerelease})
+		}
+		c.repos[githubRepoKey(apiURL, repo.owner, repo.name)] = cached
+	}
+
+	return nil
+}
+
+// githubGraphQLURL returns the GraphQL endpoint of the GitHub instance with
+// the given REST API URL, e.g. "https://ghe.example.com/api/graphql" for
+// "https://ghe.example.com/api/v3".
+func githubGraphQLURL(apiURL string) string {
+	return strings.TrimSuffix(apiURL, "/v3") + "/graphql"
+}
+
+// prefetchGitHubReleases fetches the releases of the GitHub bins with a token
+// in a single request per GitHub instance, so checking many bins for updates
+// doesn't cost a request each. Failures are not fatal: the providers fall
+// back to the REST API.
+func (b *Bine) prefetchGitHubReleases(ctx context.Context, bins []*bin) {
+	if b.ghReleases == nil {
+		return
+	}
+	b.ghReleases.reset()
+
+	type instance struct{ apiURL, token string }
+	var instances []instance
+	repos := map[instance][]githubRepository{}
+	seen := map[string]bool{}
+	for _, bin := range bins {
+		p, ok := bin.provider.(*githubProvider)
+		if !ok || p.token == "" {
+			continue
+		}
+		owner, name, err := githubRepo(bin)
+		if err != nil {
+			continue
+		}
+		key := githubRepoKey(p.api(), owner, name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		i := instance{p.api(), p.token}
+		if _, ok := repos[i]; !ok {
+			instances = append(instances, i)
+		}
+		repos[i] = append(repos[i], githubRepository{owner: owner, name: name})
+	}
+
+	for _, i := range instances {
+		if err := b.ghReleases.fetch(ctx, b.client, i.apiURL, i.token, repos[i]); err != nil {
+			b.logger.V(1).Info("Could not fetch GitHub releases with GraphQL.", "api", i.apiURL, "err", err)
+		}
+	}
+}
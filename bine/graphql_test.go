@@ -0,0 +1,99 @@
+package bine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestPrefetchGitHubReleases(t *testing.T) {
+	var graphQLRequests, restRequests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghe-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/api/graphql" {
+			var req struct {
+				Query     string            `json:"query"`
+				Variables map[string]string `json:"variables"`
+			}
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&req))
+			graphQLRequests = append(graphQLRequests, req.Variables["name0"], req.Variables["name1"], req.Variables["name2"])
+
+			release := func(tag string, prerelease, draft bool) map[string]any {
+				return map[string]any{"tagName": tag, "isPrerelease": prerelease, "isDraft": draft}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"r0": map[string]any{"releases": map[string]any{
+						"pageInfo": map[string]any{"hasNextPage": false},
+						"nodes": []any{
+							release("v3.0.0", false, true),
+							release("v2.1.0-rc.1", true, false),
+							release("v2.0.0", false, false),
+						},
+					}},
+					"r1": map[string]any{"releases": map[string]any{
+						"pageInfo": map[string]any{"hasNextPage": false},
+						"nodes":    []any{release("v1.0.0", false, false)},
+					}},
+					"r2": nil,
+				},
+				"errors": []any{map[string]any{"message": "Could not resolve to a Repository with the name 'tools/private'."}},
+			})
+			return
+		}
+		restRequests = append(restRequests, r.URL.Path)
+		if r.URL.Path == "/api/v3/repos/tools/private/releases" {
+			_ = json.NewEncoder(w).Encode([]githubRelease{{TagName: "v0.2.0"}})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	const config = `{
+		"project": "test",
+		"bins": [
+			{"name": "cli", "url": "%[1]s/tools/cli", "provider": "github", "version": "1.0.0", "asset_pattern": "{name}"},
+			{"name": "lint", "url": "%[1]s/tools/lint", "provider": "github", "version": "1.0.0", "asset_pattern": "{name}"},
+			{"name": "private", "url": "%[1]s/tools/private", "provider": "github", "version": "0.1.0", "asset_pattern": "{name}"}
+		]
+	}`
+	tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", fmt.Sprintf(config, server.URL)))
+	t.Chdir(tmpDir.Path())
+
+	b, err := NewWithOptions(
+		WithCacheDir(t.TempDir()),
+		WithGitHubHostTokens(strings.TrimPrefix(server.URL, "http://")+"=ghe-token"),
+	)
+	assert.NilError(t, err)
+
+	items, err := b.List(t.Context(), false, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(items), 2)
+	assert.Equal(t, items[0].Name, "cli")
+	assert.Equal(t, items[0].Latest, "v2.0.0")
+	assert.Equal(t, items[1].Name, "private")
+	assert.Equal(t, items[1].Latest, "v0.2.0")
+
+	// One query for all the bins, and the REST API for the missing repository.
+	assert.DeepEqual(t, graphQLRequests, []string{"cli", "lint", "private"})
+	assert.DeepEqual(t, restRequests, []string{"/api/v3/repos/tools/private/releases"})
+
+	// The releases aren't kept after the check.
+	_, ok := b.ghReleases.get(server.URL+"/api/v3", "tools", "cli")
+	assert.Assert(t, !ok)
+}
+
+func TestGitHubGraphQLURL(t *testing.T) {
+	assert.Equal(t, githubGraphQLURL(githubAPIURL), "https://api.github.com/graphql")
+	assert.Equal(t, githubGraphQLURL("https://ghe.example.com/api/v3"), "https://ghe.example.com/api/graphql")
+}
@@ -0,0 +1,194 @@
+package bine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// httpProvider downloads assets from arbitrary web servers, e.g. vendor CDNs,
+// using the URL template configured in the download_url field.
+//
+// The latest version is only available when versions_url is configured. The
+// versions are extracted from the page with either a regular expression or a
+// JSON path.
+type httpProvider struct {
+	client *http.Client
+
+	// versionsRegex extracts the versions from the page at versions_url.
+	versionsRegex *regexp.Regexp
+}
+
+var _ binProvider = &httpProvider{}
+
+func newHTTPProvider(client *http.Client, b *bin) (*httpProvider, error) {
+	if b.DownloadURL == "" {
+		return nil, fmt.Errorf("download_url is required by the %q provider", providerHTTP)
+	}
+	if b.AssetPattern != "" {
+		return nil, errors.New("asset_pattern cannot be used with download_url")
+	}
+
+	p := &httpProvider{client: client}
+	if b.VersionsURL == "" {
+		return p, nil
+	}
+
+	switch {
+	case b.VersionsRegex != "" && b.VersionsJSONPath != "":
+		return nil, errors.New("versions_regex and versions_json_path are mutually exclusive")
+	case b.VersionsRegex != "":
+		re, err := regexp.Compile(b.VersionsRegex)
+		if err != nil {
+			return nil, fmt.Errorf("versions_regex: %v", err)
+		}
+		p.versionsRegex = re
+	case b.VersionsJSONPath == "":
+		return nil, errors.New("versions_url requires versions_regex or versions_json_path")
+	}
+
+	return p, nil
+}
+
+// downloadURL returns the expanded download URL. Other assets, e.g. checksum
+// files or signatures, are expected to be found next to it.
+func (p *httpProvider) downloadURL(_ context.Context, b *bin) (string, error) {
+	u, err := url.Parse(b.expandedDownloadURL)
+	if err != nil {
+		return "", fmt.Errorf("parse download URL: %v", err)
+	}
+	if path.Base(u.Path) == b.asset {
+		return b.expandedDownloadURL, nil
+	}
+
+	u = u.JoinPath("..", b.asset)
+	u.RawQuery = ""
+
+	return u.String(), nil
+}
+
+func (p *httpProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
+	if bin.VersionsURL == "" {
+		return "", errors.New("versions_url is not configured")
+	}
+
+	versionsURL := strings.ReplaceAll(bin.VersionsURL, "{name}", bin.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, versionsURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned status %d", versionsURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read body: %v", err)
+	}
+
+	var candidates []string
+	if p.versionsRegex != nil {
+		candidates = matchVersions(p.versionsRegex, body)
+	} else {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return "", fmt.Errorf("decode versions: %v", err)
+		}
+		candidates = selectJSONPath(doc, bin.VersionsJSONPath)
+	}
+
+	latestVersion := latestVersionOf(bin, candidates)
+	if latestVersion == "" {
+		return "", fmt.Errorf("no valid %s versions found in %s", bin.versionKind(), versionsURL)
+	}
+
+	return latestVersion, nil
+}
+
+// matchVersions returns the first submatch of every match of re, or the whole
+// match when re has no capture groups.
+func matchVersions(re *regexp.Regexp, body []byte) []string {
+	var versions []string
+	for _, match := range re.FindAllSubmatch(body, -1) {
+		if len(match) > 1 {
+			versions = append(versions, string(match[1]))
+		} else {
+			versions = append(versions, string(match[0]))
+		}
+	}
+
+	return versions
+}
+
+// selectJSONPath returns the strings found at the dot-separated path in doc,
+// e.g. "versions.*.version". The "*" element matches every item of an array
+// or every value of an object.
+func selectJSONPath(doc any, jsonPath string) []string {
+	nodes := []any{doc}
+	for key := range strings.SplitSeq(jsonPath, ".") {
+		if key == "" {
+			continue
+		}
+		var next []any
+		for _, node := range nodes {
+			switch v := node.(type) {
+			case []any:
+				if key == "*" {
+					next = append(next, v...)
+				}
+			case map[string]any:
+				if key == "*" {
+					for _, value := range v {
+						next = append(next, value)
+					}
+				} else if value, ok := v[key]; ok {
+					next = append(next, value)
+				}
+			}
+		}
+		nodes = next
+	}
+
+	var versions []string
+	for _, node := range nodes {
+		if s, ok := node.(string); ok {
+			versions = append(versions, s)
+		}
+	}
+
+	return versions
+}
+
+// latestVersionOf returns the highest version considered by the binary,
+// without the "v" prefix, according to its version scheme. Invalid versions
+// are ignored.
+func latestVersionOf(bin *bin, versions []string) string {
+	scheme := bin.versionScheme()
+	var latestVersion string
+	for _, version := range versions {
+		version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+		if !bin.considers(version) {
+			continue
+		}
+		if latestVersion == "" || scheme.compare(version, latestVersion) > 0 {
+			latestVersion = version
+		}
+	}
+
+	return latestVersion
+}
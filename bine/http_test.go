@@ -0,0 +1,139 @@
+package bine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	modifyRuntime(t, "linux", "amd64")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tool/index.html":
+			_, _ = w.Write([]byte(`<a href="/tool/1.9.0/">1.9.0</a> <a href="/tool/1.10.0/">1.10.0</a> <a href="/tool/2.0.0-beta1/">2.0.0-beta1</a>`))
+		case "/tool/index.json":
+			_, _ = w.Write([]byte(`{"versions": {"1.9.0": {"version": "1.9.0"}, "1.10.1": {"version": "1.10.1"}, "2.0.0-rc.1": {"version": "2.0.0-rc.1"}}}`))
+		case "/dist/index.json":
+			_, _ = w.Write([]byte(`[{"version": "v20.1.0"}, {"version": "v22.0.0"}, {"version": "v21.7.3"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	newTool := func(t *testing.T, b *bin) *bin {
+		t.Helper()
+		b.Name = "tool"
+		b.Version = "1.0.0"
+		b.DownloadURL = "https://releases.example.com/{name}/{version}/{name}_{version}_{goos}_{goarch}.zip?download=1"
+		(&namer{}).run([]*bin{b})
+		assert.NilError(t, b.loadProvider(providerOptions{client: server.Client()}))
+		return b
+	}
+
+	t.Run("Expands the download URL", func(t *testing.T) {
+		tool := newTool(t, &bin{ChecksumPattern: "{name}_{version}_SHA256SUMS"})
+		assert.Equal(t, tool.asset, "tool_1.0.0_linux_amd64.zip")
+
+		downloadURL, err := tool.provider.downloadURL(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, downloadURL, "https://releases.example.com/tool/1.0.0/tool_1.0.0_linux_amd64.zip?download=1")
+
+		checksumsURL, err := sidecarURL(t.Context(), tool, tool.checksumAsset)
+		assert.NilError(t, err)
+		assert.Equal(t, checksumsURL, "https://releases.example.com/tool/1.0.0/tool_1.0.0_SHA256SUMS")
+	})
+
+	t.Run("Finds the latest version with a regex", func(t *testing.T) {
+		tool := newTool(t, &bin{
+			VersionsURL:   server.URL + "/{name}/index.html",
+			VersionsRegex: `href="/tool/([^/]+)/"`,
+		})
+
+		latest, err := tool.provider.latestVersion(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.10.0")
+	})
+
+	t.Run("Finds the latest version with a JSON path", func(t *testing.T) {
+		tool := newTool(t, &bin{
+			VersionsURL:      server.URL + "/{name}/index.json",
+			VersionsJSONPath: "versions.*.version",
+		})
+
+		latest, err := tool.provider.latestVersion(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.10.1")
+	})
+
+	t.Run("Finds the latest version in a JSON array", func(t *testing.T) {
+		tool := newTool(t, &bin{
+			VersionsURL:      server.URL + "/dist/index.json",
+			VersionsJSONPath: "*.version",
+		})
+
+		latest, err := tool.provider.latestVersion(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "22.0.0")
+	})
+
+	t.Run("Fails without versions_url", func(t *testing.T) {
+		tool := newTool(t, &bin{})
+
+		_, err := tool.provider.latestVersion(t.Context(), tool)
+		assert.Error(t, err, "versions_url is not configured")
+	})
+
+	t.Run("Fails when no version matches", func(t *testing.T) {
+		tool := newTool(t, &bin{
+			VersionsURL:   server.URL + "/tool/index.html",
+			VersionsRegex: `release-([0-9.]+)`,
+		})
+
+		_, err := tool.provider.latestVersion(t.Context(), tool)
+		assert.ErrorContains(t, err, "no valid non-prerelease semver versions found")
+	})
+}
+
+func TestLoadProviderHTTP(t *testing.T) {
+	tests := []struct {
+		name string
+		bin  *bin
+		err  string
+	}{
+		{
+			name: "Requires download_url",
+			bin:  &bin{Name: "tool", Provider: "http"},
+			err:  `download_url is required by the "http" provider`,
+		},
+		{
+			name: "Rejects asset_pattern",
+			bin:  &bin{Name: "tool", DownloadURL: "https://example.com/tool", AssetPattern: "tool"},
+			err:  "asset_pattern cannot be used with download_url",
+		},
+		{
+			name: "Requires a way to extract versions",
+			bin:  &bin{Name: "tool", DownloadURL: "https://example.com/tool", VersionsURL: "https://example.com/"},
+			err:  "versions_url requires versions_regex or versions_json_path",
+		},
+		{
+			name: "Rejects both ways to extract versions",
+			bin:  &bin{Name: "tool", DownloadURL: "https://example.com/tool", VersionsURL: "https://example.com/", VersionsRegex: "(.+)", VersionsJSONPath: "*"},
+			err:  "versions_regex and versions_json_path are mutually exclusive",
+		},
+		{
+			name: "Rejects invalid regexes",
+			bin:  &bin{Name: "tool", DownloadURL: "https://example.com/tool", VersionsURL: "https://example.com/", VersionsRegex: "("},
+			err:  "versions_regex: error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, tc.bin.loadProvider(providerOptions{}), tc.err)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package bine
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/renameio/v2"
+)
+
+// maxCachedResponseSize is the size of the largest response body cached.
+const maxCachedResponseSize = 10 << 20
+
+type responseCacheKey struct{}
+
+// withResponseCache allows the responses to the GET requests made with ctx to
+// be cached. It's used for version lookups, downloads are never cached.
+func withResponseCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseCacheKey{}, true)
+}
+
+// responseCache is an http.RoundTripper that stores responses in the cache
+// directory along with their ETag and Last-Modified headers, and revalidates
+// them with conditional requests: a 304 reply doesn't count against the rate
+// limit of the GitHub API. Stored responses younger than maxAge are served
+// without any request.
+type responseCache struct {
+	next http.RoundTripper
+
+	// dir is where responses are stored, caching is disabled if empty.
+	dir    string
+	maxAge time.Duration
+
+	now func() time.Time
+}
+
+var _ http.RoundTripper = &responseCache{}
+
+// cachedResponse is the document stored for every response.
+type cachedResponse struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// Stored is when the response was stored or last revalidated.
+	Stored time.Time `json:"stored"`
+}
+
+func (c *responseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !c.cacheable(req) {
+		return c.next.RoundTrip(req)
+	}
+
+	path := c.path(req)
+	cached := c.load(path, req.URL.String())
+	if cached != nil && c.maxAge > 0 && c.clock().Sub(cached.Stored) < c.maxAge {
+		return cached.response(req), nil
+	}
+
+	outgoing := req
+	if cached != nil {
+		outgoing = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			outgoing.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			outgoing.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := c.next.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		cached.Stored = c.clock()
+		c.store(path, cached)
+		return cached.response(req), nil
+	case resp.StatusCode != http.StatusOK:
+		return resp, nil
+	case resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "" && c.maxAge == 0:
+		return resp, nil // Nothing to revalidate with.
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponseSize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedResponseSize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	c.store(path, &cachedResponse{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Stored:     c.clock(),
+	})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+func (c *responseCache) cacheable(req *http.Request) bool {
+	if c.dir == "" || req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return false
+	}
+	ok, _ := req.Context().Value(responseCacheKey{}).(bool)
+	return ok
+}
+
+// path returns the path of the document of the request. Responses depend on
+// the credentials and the media type requested too, e.g. for private
+// repositories.
+func (c *responseCache) path(req *http.Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s", req.Method, req.URL, req.Header.Get("Authorization"), req.Header.Get("Accept"))
+	return filepath.Join(c.dir, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+// load returns the response stored for the URL, if any. Unreadable documents
+// are ignored, they are replaced with the next response.
+func (c *responseCache) load(path, url string) *cachedResponse {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(blob, &cached); err != nil || cached.URL != url {
+		return nil
+	}
+	return &cached
+}
+
+// store writes the document of a response. Failures are ignored since the
+// cache is only an optimization.
+func (c *responseCache) store(path string, cached *cachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return
+	}
+	_ = renameio.WriteFile(path, data, 0o640, renameio.WithStaticPermissions(0o640))
+}
+
+func (c *responseCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
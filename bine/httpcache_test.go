@@ -0,0 +1,120 @@
+package bine
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+	var requests, revalidations int
+	body := `{"versions": ["1.0.0"]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		etag := `"` + body + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 10, 17, 12, 0, 0, 0, time.UTC)
+	cache := &responseCache{
+		next: http.DefaultTransport,
+		dir:  t.TempDir(),
+		now:  func() time.Time { return now },
+	}
+	client := &http.Client{Transport: cache}
+
+	get := func(t *testing.T, cached bool) string {
+		t.Helper()
+		ctx := t.Context()
+		if cached {
+			ctx = withResponseCache(ctx)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/versions.json", nil)
+		assert.NilError(t, err)
+		resp, err := client.Do(req)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		blob, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		return string(blob)
+	}
+
+	t.Run("Ignores requests without opt-in", func(t *testing.T) {
+		assert.Equal(t, get(t, false), body)
+		entries, err := os.ReadDir(cache.dir)
+		assert.NilError(t, err)
+		assert.Equal(t, len(entries), 0)
+	})
+
+	t.Run("Revalidates stored responses", func(t *testing.T) {
+		requests, revalidations = 0, 0
+		assert.Equal(t, get(t, true), body)
+		assert.Equal(t, get(t, true), body)
+		assert.Equal(t, requests, 2)
+		assert.Equal(t, revalidations, 1)
+
+		body = `{"versions": ["1.0.0", "1.1.0"]}`
+		assert.Equal(t, get(t, true), body)
+		assert.Equal(t, revalidations, 1)
+	})
+
+	t.Run("Serves fresh responses without requests", func(t *testing.T) {
+		cache.maxAge = time.Hour
+		requests = 0
+		now = now.Add(30 * time.Minute)
+		assert.Equal(t, get(t, true), body)
+		assert.Equal(t, requests, 0)
+
+		now = now.Add(time.Hour)
+		assert.Equal(t, get(t, true), body)
+		assert.Equal(t, requests, 1)
+	})
+}
+
+func TestResponseCacheLastModified(t *testing.T) {
+	modified := time.Date(2024, 10, 17, 12, 0, 0, 0, time.UTC)
+	var requests, conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-Modified-Since") != "" {
+			conditional++
+		}
+		http.ServeContent(w, r, "versions.txt", modified, strings.NewReader("1.0.0\n"))
+	}))
+	defer server.Close()
+
+	cache := &responseCache{next: http.DefaultTransport, dir: t.TempDir()}
+	client := &http.Client{Transport: cache}
+
+	for range 2 {
+		req, err := http.NewRequestWithContext(withResponseCache(t.Context()), http.MethodGet, server.URL, nil)
+		assert.NilError(t, err)
+		resp, err := client.Do(req)
+		assert.NilError(t, err)
+		blob, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.Equal(t, string(blob), "1.0.0\n")
+	}
+	assert.Equal(t, requests, 2)
+	assert.Equal(t, conditional, 1)
+
+	entries, err := os.ReadDir(cache.dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+}
@@ -14,6 +14,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -48,11 +49,20 @@ func goInstall(ctx context.Context, b *bin, binDir string) error {
 	}
 	defer func() { _ = os.RemoveAll(tmpBinDir) }()
 
-	cmd := execCommand(ctx, goBin, "install", packageName)
+	settings := b.goBuildSettings()
+	for _, kv := range settings.env() {
+		if k, _, ok := strings.Cut(kv, "="); !ok || k == "" {
+			return fmt.Errorf("invalid go_env entry %q, expected KEY=VALUE", kv)
+		}
+	}
+
+	args := append([]string{"install"}, settings.flags()...)
+	cmd := execCommand(ctx, goBin, append(args, packageName)...)
 
 	// Set GOBIN to install the binary there. fakeExecCommand sets cmd.Env so
 	// we can't assume it's empty.
 	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, settings.env()...)
 	cmd.Env = append(cmd.Env, "GOBIN="+tmpBinDir)
 
 	var stderr bytes.Buffer
@@ -79,13 +89,122 @@ func goInstall(ctx context.Context, b *bin, binDir string) error {
 	return nil
 }
 
+// goBuildSettings are the settings a go_package bin is built with. They are
+// recorded in the version marker so that changing them triggers a reinstall.
+type goBuildSettings struct {
+	Env      []string `json:"env,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Ldflags  string   `json:"ldflags,omitempty"`
+	Trimpath bool     `json:"trimpath,omitempty"`
+}
+
+// goBuildSettings returns the build settings of the bin, or nil if it uses
+// the defaults of "go install".
+func (b bin) goBuildSettings() *goBuildSettings {
+	if len(b.GoEnv) == 0 && len(b.GoTags) == 0 && b.GoLdflags == "" && !b.Trimpath {
+		return nil
+	}
+
+	return &goBuildSettings{
+		Env:      b.GoEnv,
+		Tags:     b.GoTags,
+		Ldflags:  b.GoLdflags,
+		Trimpath: b.Trimpath,
+	}
+}
+
+func (s *goBuildSettings) env() []string {
+	if s == nil {
+		return nil
+	}
+	return s.Env
+}
+
+// flags returns the flags of "go install" that apply the settings.
+func (s *goBuildSettings) flags() []string {
+	if s == nil {
+		return nil
+	}
+
+	var flags []string
+	if len(s.Tags) > 0 {
+		flags = append(flags, "-tags="+strings.Join(s.Tags, ","))
+	}
+	if s.Ldflags != "" {
+		flags = append(flags, "-ldflags="+s.Ldflags)
+	}
+	if s.Trimpath {
+		flags = append(flags, "-trimpath")
+	}
+
+	return flags
+}
+
+func (s *goBuildSettings) equal(other *goBuildSettings) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+
+	return slices.Equal(s.Env, other.Env) &&
+		slices.Equal(s.Tags, other.Tags) &&
+		s.Ldflags == other.Ldflags &&
+		s.Trimpath == other.Trimpath
+}
+
+func (s *goBuildSettings) String() string {
+	if s == nil {
+		return "none"
+	}
+	return strings.Join(append(slices.Clone(s.Env), s.flags()...), " ")
+}
+
 // goInstalledVersion returns the version of the Go module embedded in a binary
 // by running "go version -m". This is used to determine the resolved version
 // after installing a Go tool with @latest.
 func goInstalledVersion(ctx context.Context, binaryPath string) (string, error) {
+	info, err := readGoBuildInfo(ctx, binaryPath)
+	if err != nil {
+		return "", err
+	}
+
+	return info.semver()
+}
+
+// goBuildInfo is the part of the build information embedded in a Go binary
+// that bine cares about.
+type goBuildInfo struct {
+	// Path is the import path of the main package.
+	Path string
+	// Module and Version identify the main module.
+	Module  string
+	Version string
+	// Sum is the "h1:" hash of the main module, as found in go.sum files.
+	Sum string
+}
+
+// semver returns the version of the main module without the "v" prefix.
+func (i *goBuildInfo) semver() (string, error) {
+	if i.Version == "" {
+		return "", errors.New("could not determine installed version from 'go version -m' output")
+	}
+
+	rawVersion := strings.TrimPrefix(i.Version, "v")
+	// Validate that the extracted version is a proper semver.
+	// Versions like "(devel)" or pseudo-versions are not useful for
+	// upgrade comparisons.
+	if semver.Canonical("v"+rawVersion) == "" {
+		return "", fmt.Errorf("non-semver version %q reported by 'go version -m'", i.Version)
+	}
+
+	return rawVersion, nil
+}
+
+// readGoBuildInfo reads the build information of a Go binary by running
+// "go version -m".
+func readGoBuildInfo(ctx context.Context, binaryPath string) (*goBuildInfo, error) {
 	goBin, err := exec.LookPath("go")
 	if err != nil {
-		return "", fmt.Errorf("cannot find 'go' command: %v", err)
+		return nil, fmt.Errorf("cannot find 'go' command: %v", err)
 	}
 
 	cmd := execCommand(ctx, goBin, "version", "-m", binaryPath)
@@ -94,29 +213,29 @@ func goInstalledVersion(ctx context.Context, binaryPath string) (string, error)
 	cmd.Stdout = &stdout
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("go version -m: %v", err)
+		return nil, fmt.Errorf("go version -m: %v", err)
 	}
 
-	// Parse "go version -m" output to find the "mod" line.
+	// Parse "go version -m" output to find the "path" and "mod" lines.
 	// Example output:
 	//   /path/to/binary: go1.21.0
 	//           path    github.com/foo/bar/cmd/tool
 	//           mod     github.com/foo/bar      v1.2.3  h1:...
+	info := &goBuildInfo{}
 	for line := range strings.SplitSeq(stdout.String(), "\n") {
 		fields := strings.Fields(line)
-		if len(fields) >= 3 && fields[0] == "mod" {
-			rawVersion := strings.TrimPrefix(fields[2], "v")
-			// Validate that the extracted version is a proper semver.
-			// Versions like "(devel)" or pseudo-versions are not useful for
-			// upgrade comparisons.
-			if semver.Canonical("v"+rawVersion) == "" {
-				return "", fmt.Errorf("non-semver version %q reported by 'go version -m'", fields[2])
+		switch {
+		case len(fields) >= 2 && fields[0] == "path":
+			info.Path = fields[1]
+		case len(fields) >= 3 && fields[0] == "mod":
+			info.Module, info.Version = fields[1], fields[2]
+			if len(fields) >= 4 {
+				info.Sum = fields[3]
 			}
-			return rawVersion, nil
 		}
 	}
 
-	return "", errors.New("could not determine installed version from 'go version -m' output")
+	return info, nil
 }
 
 func defaultGoBinaryName(pkg string) string {
@@ -171,60 +290,196 @@ func replaceFile(src, dst string) error {
 	return nil
 }
 
-func binInstall(ctx context.Context, client *http.Client, b *bin, binPath string) error {
-	downloadURL, err := b.provider.downloadURL(b)
+// newDownloadRequest creates the request that downloads a release asset of the
+// binary, authenticated by its provider when needed.
+func newDownloadRequest(ctx context.Context, b *bin, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to generate download URL: %v", err)
+		return nil, err
+	}
+	if auth, ok := b.provider.(authenticator); ok {
+		auth.authenticate(req)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	return req, nil
+}
+
+// binInstall downloads the release asset of the binary and extracts it to
+// binPath. When locked is provided, the downloaded archive and the extracted
+// binary must match its checksums. It returns a description of the installed
+// asset that can be recorded in the lock file.
+func binInstall(ctx context.Context, client *http.Client, b *bin, binPath string, locked *lockedAsset) (*lockedAsset, error) {
+	// Stick to the asset selected when the lock file was written.
+	if b.asset == "" && b.autoAsset != nil && locked != nil {
+		b.asset = locked.Asset
+	}
+	downloadURL, err := b.provider.downloadURL(ctx, b)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to generate download URL: %v", err)
+	}
+	if downloadURL == "" {
+		if inst, ok := b.provider.(installer); ok {
+			return providerInstall(ctx, inst, b, binPath, locked)
+		}
+		return nil, errors.New("failed to generate download URL: provider returned no URL")
+	}
+
+	req, err := newDownloadRequest(ctx, b, downloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	// Download the asset.
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to download asset from %q: %v", downloadURL, err)
+		return nil, fmt.Errorf("failed to download asset from %q: %v", downloadURL, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed: status %s (%s)", resp.Status, downloadURL)
+		return nil, fmt.Errorf("download failed: status %s (%s)", resp.Status, downloadURL)
 	}
 	f, err := os.CreateTemp("", "downloaded-*-"+filepath.Base(downloadURL))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() { _ = os.Remove(f.Name()) }()
-	if _, err := io.Copy(f, resp.Body); err != nil {
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
 		_ = f.Close()
-		return fmt.Errorf("failed to write to temporary file: %w", err)
+		return nil, fmt.Errorf("failed to write to temporary file: %w", err)
 	}
 	defer func() { _ = f.Close() }()
 
+	installed := &lockedAsset{
+		Tag:           b.tag(),
+		URL:           downloadURL,
+		Asset:         b.asset,
+		ArchiveSHA256: hex.EncodeToString(h.Sum(nil)),
+	}
+	var checksums []byte
+	if b.checksumAsset != "" {
+		if checksums, err = fetchChecksumFile(ctx, client, b); err != nil {
+			return nil, err
+		}
+	}
+	if b.Signature != nil {
+		if installed.Signer, err = verifySignature(ctx, client, b, f.Name(), checksums); err != nil {
+			return nil, err
+		}
+	}
+	if checksums != nil {
+		if err := verifyChecksums(b, checksums, f.Name()); err != nil {
+			return nil, err
+		}
+	}
+	if pinned, err := b.pinnedChecksum(); err != nil {
+		return nil, err
+	} else if pinned != nil {
+		if err := verifyPinnedChecksum(*pinned, f.Name()); err != nil {
+			return nil, err
+		}
+	}
+	if locked != nil && locked.ArchiveSHA256 != installed.ArchiveSHA256 {
+		return nil, fmt.Errorf("archive checksum mismatch: lock file has %s, downloaded %s", locked.ArchiveSHA256, installed.ArchiveSHA256)
+	}
+
 	// Reset file pointer to the beginning so we can extract.
 	if _, err := f.Seek(0, io.SeekStart); err != nil {
 		_ = f.Close()
-		return fmt.Errorf("failed to reset file pointer: %v", err)
+		return nil, fmt.Errorf("failed to reset file pointer: %v", err)
 	}
 
 	tmpBin, err := os.CreateTemp(filepath.Dir(binPath), ".bine-bin-install-*")
 	if err != nil {
-		return fmt.Errorf("create temporary binary: %v", err)
+		return nil, fmt.Errorf("create temporary binary: %v", err)
 	}
 	tmpBinPath := tmpBin.Name()
 	_ = tmpBin.Close()
 	defer func() { _ = os.Remove(tmpBinPath) }()
 
 	if err := extract(ctx, f, tmpBinPath); err != nil {
-		return fmt.Errorf("extract failed: %v", err)
+		return nil, fmt.Errorf("extract failed: %v", err)
+	}
+
+	if sum, err := checksum(tmpBinPath); err != nil {
+		return nil, fmt.Errorf("checksum: %v", err)
+	} else {
+		installed.BinarySHA256 = sum
+	}
+	if locked != nil && locked.BinarySHA256 != installed.BinarySHA256 {
+		return nil, fmt.Errorf("binary checksum mismatch: lock file has %s, extracted %s", locked.BinarySHA256, installed.BinarySHA256)
 	}
 
 	if err := replaceFile(tmpBinPath, binPath); err != nil {
-		return fmt.Errorf("move installed binary: %v", err)
+		return nil, fmt.Errorf("move installed binary: %v", err)
 	}
 
-	return nil
+	return installed, nil
+}
+
+// providerInstall lets the provider write the binary itself, e.g. when it
+// isn't published as a downloadable asset. There is no archive, so the pinned
+// and locked archive checksums apply to the binary. Binaries built from source
+// aren't reproducible byte for byte, so they are locked by commit instead.
+func providerInstall(ctx context.Context, inst installer, b *bin, binPath string, locked *lockedAsset) (*lockedAsset, error) {
+	if b.checksumAsset != "" || b.Signature != nil {
+		return nil, errors.New("checksum files and signatures require a download URL")
+	}
+
+	tmpBin, err := os.CreateTemp(filepath.Dir(binPath), ".bine-bin-install-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temporary binary: %v", err)
+	}
+	tmpBinPath := tmpBin.Name()
+	_ = tmpBin.Close()
+	defer func() { _ = os.Remove(tmpBinPath) }()
+
+	commit, err := inst.install(ctx, b, tmpBinPath)
+	if err != nil {
+		return nil, fmt.Errorf("provider install failed: %v", err)
+	}
+	if info, err := os.Stat(tmpBinPath); err != nil {
+		return nil, fmt.Errorf("provider install failed: %v", err)
+	} else if info.Size() == 0 {
+		return nil, errors.New("provider install failed: binary is empty")
+	}
+	if err := os.Chmod(tmpBinPath, 0o755); err != nil {
+		return nil, err
+	}
+
+	installed := &lockedAsset{
+		Tag:    b.tag(),
+		URL:    b.URL,
+		Asset:  b.asset,
+		Commit: commit,
+	}
+	if commit == "" {
+		sum, err := checksum(tmpBinPath)
+		if err != nil {
+			return nil, fmt.Errorf("checksum: %v", err)
+		}
+		installed.ArchiveSHA256 = sum
+		installed.BinarySHA256 = sum
+	}
+	if pinned, err := b.pinnedChecksum(); err != nil {
+		return nil, err
+	} else if pinned != nil {
+		if err := verifyPinnedChecksum(*pinned, tmpBinPath); err != nil {
+			return nil, err
+		}
+	}
+	if locked != nil && locked.Commit != installed.Commit {
+		return nil, fmt.Errorf("commit mismatch: lock file has %q, installed %q", locked.Commit, installed.Commit)
+	}
+	if locked != nil && locked.BinarySHA256 != installed.BinarySHA256 {
+		return nil, fmt.Errorf("binary checksum mismatch: lock file has %s, installed %s", locked.BinarySHA256, installed.BinarySHA256)
+	}
+
+	if err := replaceFile(tmpBinPath, binPath); err != nil {
+		return nil, fmt.Errorf("move installed binary: %v", err)
+	}
+
+	return installed, nil
 }
 
 // extract the binary from the archive file and writes it to binPath.
@@ -50,6 +50,29 @@ func TestHelperProcessWithError(t *testing.T) {
 	os.Exit(1)
 }
 
+// TestHelperProcessGoBuildSettings writes the arguments of "go install" and
+// the value of CGO_ENABLED to the installed binary.
+func TestHelperProcessGoBuildSettings(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args
+	for i, a := range args {
+		if a == "install" {
+			args = args[i+1:]
+			break
+		}
+	}
+	pkg, _, _ := strings.Cut(args[len(args)-1], "@")
+	content := fmt.Sprintf("%q CGO_ENABLED=%s", args[:len(args)-1], os.Getenv("CGO_ENABLED"))
+	if err := os.WriteFile(filepath.Join(os.Getenv("GOBIN"), defaultGoBinaryName(pkg)), []byte(content), 0o755); err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
 // TestHelperProcessGoVersionM handles the "go version -m <binary>" command in tests.
 func TestHelperProcessGoVersionM(t *testing.T) {
 	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
@@ -111,6 +134,41 @@ func TestHelperProcessInstallButFailGoVersionM(t *testing.T) {
 	os.Exit(0)
 }
 
+// TestHelperProcessGoInstallWithSum handles both "go install" and
+// "go version -m", reporting the module sum found in BINE_HELPER_SUM.
+func TestHelperProcessGoInstallWithSum(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args
+	for i, a := range args {
+		if a == "version" && i+1 < len(args) && args[i+1] == "-m" {
+			fmt.Printf("%s: go1.21.0\n", args[i+2])
+			fmt.Printf("\tpath\tgithub.com/foo/bar/cmd/tool\n")
+			fmt.Printf("\tmod\tgithub.com/foo/bar\tv1.0.0\t%s\n", os.Getenv("BINE_HELPER_SUM"))
+			os.Exit(0)
+		}
+	}
+
+	gobin := ""
+	for _, item := range os.Environ() {
+		if value, ok := strings.CutPrefix(item, "GOBIN="); ok {
+			gobin = value
+			break
+		}
+	}
+	if gobin == "" {
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(filepath.Join(gobin, "tool"), []byte("binary"), 0o755); err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
 func TestHelperProcessWithCounter(t *testing.T) {
 	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
 		return
@@ -264,6 +322,40 @@ func TestGoInstall(t *testing.T) {
 		assert.NilError(t, err)
 		assert.Equal(t, string(blob), "binary")
 	})
+
+	t.Run("Applies the Go build settings", func(t *testing.T) {
+		injectFakeExec(t, "TestHelperProcessGoBuildSettings")
+		b := &bin{
+			Name:      "migrate",
+			GoPackage: "github.com/golang-migrate/migrate/v4/cmd/migrate",
+			Version:   "v4.18.1",
+			GoEnv:     []string{"CGO_ENABLED=0"},
+			GoTags:    []string{"postgres", "sqlite3"},
+			GoLdflags: "-s -w",
+			Trimpath:  true,
+		}
+		binDir := t.TempDir()
+
+		err := goInstall(t.Context(), b, binDir)
+		assert.NilError(t, err)
+
+		blob, err := os.ReadFile(filepath.Join(binDir, "migrate"))
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), `["-tags=postgres,sqlite3" "-ldflags=-s -w" "-trimpath"] CGO_ENABLED=0`)
+	})
+
+	t.Run("Rejects invalid go_env entries", func(t *testing.T) {
+		injectFakeExec(t, "TestHelperProcessGoBuildSettings")
+		b := &bin{
+			Name:      "bine",
+			GoPackage: "github.com/artefactual-labs/bine",
+			Version:   "v0.1.0",
+			GoEnv:     []string{"CGO_ENABLED"},
+		}
+
+		err := goInstall(t.Context(), b, t.TempDir())
+		assert.Error(t, err, `invalid go_env entry "CGO_ENABLED", expected KEY=VALUE`)
+	})
 }
 
 func TestDefaultGoBinaryName(t *testing.T) {
@@ -2,12 +2,18 @@ package bine
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
 )
 
 type staticProvider struct {
@@ -15,7 +21,7 @@ type staticProvider struct {
 	err    error
 }
 
-func (p staticProvider) downloadURL(*bin) (string, error) {
+func (p staticProvider) downloadURL(context.Context, *bin) (string, error) {
 	return "", nil
 }
 
@@ -244,3 +250,73 @@ func TestUpgradeOneOnlyUpdatesRequestedBin(t *testing.T) {
 	_, err = os.Stat(filepath.Join(b.BinDir, "other"))
 	assert.Assert(t, os.IsNotExist(err))
 }
+
+func TestLatestAssetBin(t *testing.T) {
+	versions := []string{"1.0.0", "1.1.0"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/versions.json" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"versions": versions})
+			return
+		}
+		version, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/tool/"), "/tool")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("tool " + version))
+	}))
+	defer server.Close()
+
+	const config = `{
+		"project": "test",
+		"bins": [
+			{
+				"name": "tool",
+				"version": "latest",
+				"download_url": "%[1]s/tool/{version}/tool",
+				"versions_url": "%[1]s/versions.json",
+				"versions_json_path": "versions.*"
+			}
+		]
+	}`
+	tmpDir := fs.NewDir(t, "bine", fs.WithFile(".bine.json", fmt.Sprintf(config, server.URL)))
+	t.Chdir(tmpDir.Path())
+
+	b, err := NewWithOptions(WithCacheDir(t.TempDir()))
+	assert.NilError(t, err)
+	installed := func(t *testing.T) string {
+		t.Helper()
+		blob, err := os.ReadFile(filepath.Join(b.BinDir, "tool"))
+		assert.NilError(t, err)
+		return string(blob)
+	}
+
+	_, err = b.Get(t.Context(), "tool")
+	assert.NilError(t, err)
+	assert.Equal(t, installed(t), "tool 1.1.0")
+
+	tool, err := b.load("tool")
+	assert.NilError(t, err)
+	marker, err := b.readVersionMarker(tool)
+	assert.NilError(t, err)
+	assert.Equal(t, marker.ResolvedVersion, "1.1.0")
+
+	items, err := b.List(t.Context(), false, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(items), 0)
+
+	versions = append(versions, "1.2.0")
+	items, err = b.List(t.Context(), false, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(items), 1)
+	assert.Equal(t, items[0].Version, "v1.1.0")
+	assert.Equal(t, items[0].Latest, "v1.2.0")
+
+	// Upgrades don't rewrite the configuration file.
+	_, err = b.Upgrade(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, installed(t), "tool 1.2.0")
+	blob, err := os.ReadFile(tmpDir.Join(".bine.json"))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(blob), `"version": "latest"`))
+}
@@ -1,14 +1,17 @@
 package bine
 
 type binTemplate struct {
-	AssetPattern string
-	TagPattern   string
-	Modifiers    map[string]map[string]string
+	AssetPattern    string
+	TagPattern      string
+	ChecksumPattern string
+	VersionScheme   string
+	Modifiers       map[string]map[string]string
 }
 
 var knownAssetTemplates = map[string]binTemplate{
 	"https://github.com/rhysd/actionlint": {
-		AssetPattern: "{name}_{version}_{goos}_{goarch}.tar.gz",
+		AssetPattern:    "{name}_{version}_{goos}_{goarch}.tar.gz",
+		ChecksumPattern: "{name}_{version}_checksums.txt",
 	},
 	"https://release.ariga.io/atlas": {
 		AssetPattern: "atlas-{goos}-{goarch}-v{version}",
@@ -23,13 +26,16 @@ var knownAssetTemplates = map[string]binTemplate{
 		AssetPattern: "{name}_{version}_{os}_{arch}.tar.gz",
 	},
 	"https://github.com/golangci/golangci-lint": {
-		AssetPattern: "{name}-{version}-{goos}-{goarch}.tar.gz",
+		AssetPattern:    "{name}-{version}-{goos}-{goarch}.tar.gz",
+		ChecksumPattern: "{name}-{version}-checksums.txt",
 	},
 	"https://github.com/goreleaser/goreleaser": {
-		AssetPattern: "{name}_{os}_{arch}.tar.gz",
+		AssetPattern:    "{name}_{os}_{arch}.tar.gz",
+		ChecksumPattern: "checksums.txt",
 	},
 	"https://github.com/gotestyourself/gotestsum": {
-		AssetPattern: "{name}_{version}_{goos}_{goarch}.tar.gz",
+		AssetPattern:    "{name}_{version}_{goos}_{goarch}.tar.gz",
+		ChecksumPattern: "{name}-{version}-checksums.txt",
 	},
 	"https://github.com/fullstorydev/grpcurl": {
 		AssetPattern: "{name}_{version}_{goos}_{goarch}.tar.gz",
@@ -80,8 +86,9 @@ var knownAssetTemplates = map[string]binTemplate{
 		AssetPattern: "{name}_{goos}_{arch}",
 	},
 	"https://github.com/astral-sh/uv": {
-		AssetPattern: "{name}-{triple}.tar.gz",
-		TagPattern:   "{version}",
+		AssetPattern:    "{name}-{triple}.tar.gz",
+		TagPattern:      "{version}",
+		ChecksumPattern: "{name}-{triple}.tar.gz.sha256",
 	},
 }
 
@@ -102,6 +109,12 @@ func applyBinTemplate(b *bin, t binTemplate) {
 	if b.TagPattern == "" {
 		b.TagPattern = t.TagPattern
 	}
+	if b.ChecksumPattern == "" {
+		b.ChecksumPattern = t.ChecksumPattern
+	}
+	if b.VersionScheme == "" {
+		b.VersionScheme = t.VersionScheme
+	}
 	if len(t.Modifiers) > 0 {
 		b.Modifiers = mergeModifiers(b.Modifiers, t.Modifiers)
 	}
@@ -110,6 +110,22 @@ func TestApplyLibraryDefaults(t *testing.T) {
 		assert.Equal(t, cfg.Bins[0].Modifiers["goos"]["darwin"], "macos")
 	})
 
+	t.Run("fills known checksum pattern", func(t *testing.T) {
+		cfg := &config{
+			Bins: []*bin{
+				{
+					Name:    "golangci-lint",
+					URL:     "https://github.com/golangci/golangci-lint",
+					Version: "2.11.2",
+				},
+			},
+		}
+
+		applyLibraryDefaults(cfg)
+
+		assert.Equal(t, cfg.Bins[0].ChecksumPattern, "{name}-{version}-checksums.txt")
+	})
+
 	t.Run("preserves user fields", func(t *testing.T) {
 		cfg := &config{
 			Bins: []*bin{
@@ -171,6 +187,16 @@ func TestApplyLibraryDefaults(t *testing.T) {
 		assert.Equal(t, cfg.Bins[0].Modifiers["goarch"]["amd64"], "x86_64")
 	})
 
+	t.Run("fills version scheme", func(t *testing.T) {
+		b := &bin{Name: "tool"}
+		applyBinTemplate(b, binTemplate{VersionScheme: schemeCalver})
+		assert.Equal(t, b.VersionScheme, schemeCalver)
+
+		b = &bin{Name: "tool", VersionScheme: schemeNumeric}
+		applyBinTemplate(b, binTemplate{VersionScheme: schemeCalver})
+		assert.Equal(t, b.VersionScheme, schemeNumeric)
+	})
+
 	t.Run("leaves unknown bins alone", func(t *testing.T) {
 		cfg := &config{
 			Bins: []*bin{
@@ -204,7 +230,7 @@ version = "0.4.0"
 `))
 	t.Chdir(tmpDir.Path())
 
-	cfg, err := loadConfig(t.Context(), nil, "")
+	cfg, err := loadConfig(t.Context(), providerOptions{})
 	assert.NilError(t, err)
 
 	assert.Equal(t, cfg.Bins[0].URL, "https://github.com/psampaz/go-mod-outdated")
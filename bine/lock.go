@@ -0,0 +1,195 @@
+package bine
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/renameio/v2"
+)
+
+const lockFileName = ".bine.lock"
+
+// lockFile pins the exact artifacts installed for every binary so that all
+// developers end up with the same bytes for the same version. It lives next to
+// the configuration file and is meant to be committed.
+type lockFile struct {
+	Bins map[string]*lockedBin `json:"bins"`
+
+	// path to the lock file on disk.
+	path string
+}
+
+// lockedBin is the lock file entry of a single binary.
+type lockedBin struct {
+	Version string `json:"version"`
+	// Constraint is the version range of the binary, if any, that Version
+	// was resolved from.
+	Constraint string `json:"constraint,omitempty"`
+	// Platforms is keyed by "{goos}/{goarch}", e.g. "linux/amd64".
+	Platforms map[string]*lockedAsset `json:"platforms,omitempty"`
+	// ModuleSum is the "h1:" hash of the Go module of go_package bins. Unlike
+	// release assets, it doesn't depend on the platform.
+	ModuleSum string `json:"module_sum,omitempty"`
+}
+
+// lockedAsset describes a downloaded release asset and the binary extracted
+// from it.
+type lockedAsset struct {
+	Tag           string `json:"tag"`
+	URL           string `json:"url"`
+	Asset         string `json:"asset"`
+	ArchiveSHA256 string `json:"archive_sha256,omitempty"`
+	BinarySHA256  string `json:"binary_sha256,omitempty"`
+	// Commit is the revision that binaries built from source were built
+	// from. Their checksums are not recorded since builds are not
+	// reproducible.
+	Commit string `json:"commit,omitempty"`
+	// Signer identifies the key that signed the asset, if verified.
+	Signer string `json:"signer,omitempty"`
+}
+
+func lockFilePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), lockFileName)
+}
+
+func platform() string {
+	return goos + "/" + goarch
+}
+
+func newLockFile(path string) *lockFile {
+	return &lockFile{
+		Bins: map[string]*lockedBin{},
+		path: path,
+	}
+}
+
+// loadLockFile reads the lock file at path. It returns nil if the file does
+// not exist.
+func loadLockFile(path string) (*lockFile, error) {
+	blob, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read lock file %q: %v", path, err)
+	}
+
+	lock := newLockFile(path)
+	if err := json.Unmarshal(blob, lock); err != nil {
+		return nil, fmt.Errorf("unmarshal lock file %q: %v", path, err)
+	}
+	if lock.Bins == nil {
+		lock.Bins = map[string]*lockedBin{}
+	}
+
+	return lock, nil
+}
+
+// entry returns the lock file entry of the binary, or nil if the binary isn't
+// locked. It fails when the lock was recorded for a different version, or
+// version range, than the one configured.
+func (l *lockFile) entry(b *bin) (*lockedBin, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	entry, ok := l.Bins[b.Name]
+	if !ok {
+		return nil, nil
+	}
+	if b.isRange() {
+		if entry.Constraint != b.Version {
+			locked := cmp.Or(entry.Constraint, entry.Version)
+			return nil, fmt.Errorf("lock file is out of date (locked %q, configured %q); run `bine lock`", locked, b.Version)
+		}
+		return entry, nil
+	}
+	if entry.Version != b.Version {
+		return nil, fmt.Errorf("lock file is out of date (locked %q, configured %q); run `bine lock`", entry.Version, b.Version)
+	}
+
+	return entry, nil
+}
+
+// asset returns the locked asset of the binary for the current platform, or
+// nil if the binary isn't locked.
+func (l *lockFile) asset(b *bin) (*lockedAsset, error) {
+	entry, err := l.entry(b)
+	if entry == nil || err != nil {
+		return nil, err
+	}
+
+	return entry.Platforms[platform()], nil
+}
+
+// moduleSum returns the locked module hash of a go_package binary, or an
+// empty string if the binary isn't locked.
+func (l *lockFile) moduleSum(b *bin) (string, error) {
+	entry, err := l.entry(b)
+	if entry == nil || err != nil {
+		return "", err
+	}
+
+	return entry.ModuleSum, nil
+}
+
+// set records the asset installed for the binary on the current platform.
+// Entries of other platforms are kept unless the version changed.
+func (l *lockFile) set(b *bin, asset *lockedAsset) {
+	entry := l.reset(b)
+	if entry.Platforms == nil {
+		entry.Platforms = map[string]*lockedAsset{}
+	}
+	entry.Platforms[platform()] = asset
+}
+
+// setModuleSum records the module hash of a go_package binary.
+func (l *lockFile) setModuleSum(b *bin, sum string) {
+	l.reset(b).ModuleSum = sum
+}
+
+// setConstraint records the version range that the locked version of the
+// binary was resolved from.
+func (l *lockFile) setConstraint(b *bin, constraint string) {
+	if entry, ok := l.Bins[b.Name]; ok {
+		entry.Constraint = constraint
+	}
+}
+
+// reset returns the entry of the binary, replacing it if it was recorded for
+// a different version.
+func (l *lockFile) reset(b *bin) *lockedBin {
+	entry, ok := l.Bins[b.Name]
+	if !ok || entry.Version != b.Version {
+		entry = &lockedBin{Version: b.Version}
+		l.Bins[b.Name] = entry
+	}
+	return entry
+}
+
+// prune removes the entries of binaries that are no longer configured or
+// that now track the latest version, which can't be locked.
+func (l *lockFile) prune(bins []*bin) {
+	configured := make(map[string]bool, len(bins))
+	for _, b := range bins {
+		configured[b.Name] = !b.isLatest()
+	}
+	for name := range l.Bins {
+		if !configured[name] {
+			delete(l.Bins, name)
+		}
+	}
+}
+
+func (l *lockFile) write() error {
+	data, err := json.MarshalIndent(l, "", "\t")
+	if err != nil {
+		return fmt.Errorf("json marshal: %v", err)
+	}
+	data = append(data, '\n')
+
+	return renameio.WriteFile(l.path, data, 0o644, renameio.WithStaticPermissions(0o644))
+}
@@ -0,0 +1,216 @@
+package bine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// assetServerProvider downloads assets from a test server.
+type assetServerProvider struct {
+	baseURL string
+}
+
+func (p assetServerProvider) downloadURL(_ context.Context, b *bin) (string, error) {
+	return p.baseURL + "/" + b.asset, nil
+}
+
+func (p assetServerProvider) latestVersion(context.Context, *bin) (string, error) {
+	return "", nil
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func newLockTestBine(t *testing.T, assets map[string]string) (*Bine, *bin) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blob, ok := assets[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(blob))
+	}))
+	t.Cleanup(server.Close)
+
+	cacheDir := t.TempDir()
+	configPath := filepath.Join(cacheDir, ".bine.json")
+	assert.NilError(t, os.WriteFile(configPath, []byte("{}"), 0o640))
+
+	tool := &bin{
+		Name:     "tool",
+		Version:  "1.0.0",
+		asset:    "tool_1.0.0",
+		provider: assetServerProvider{baseURL: server.URL},
+	}
+
+	return &Bine{
+		client:      server.Client(),
+		BinDir:      filepath.Join(cacheDir, "bin"),
+		VersionsDir: filepath.Join(cacheDir, "versions"),
+		config: &config{
+			path:   configPath,
+			format: configFormatJSON,
+			Bins:   []*bin{tool},
+		},
+	}, tool
+}
+
+func TestLoadLockFile(t *testing.T) {
+	t.Run("Returns nil when the lock file does not exist", func(t *testing.T) {
+		lock, err := loadLockFile(filepath.Join(t.TempDir(), lockFileName))
+		assert.NilError(t, err)
+		assert.Assert(t, lock == nil)
+	})
+
+	t.Run("Reads a written lock file", func(t *testing.T) {
+		modifyRuntime(t, "linux", "amd64")
+
+		path := filepath.Join(t.TempDir(), lockFileName)
+		tool := &bin{Name: "tool", Version: "1.0.0"}
+		lock := newLockFile(path)
+		lock.set(tool, &lockedAsset{Tag: "v1.0.0", Asset: "tool", ArchiveSHA256: "a", BinarySHA256: "b"})
+		assert.NilError(t, lock.write())
+
+		lock, err := loadLockFile(path)
+		assert.NilError(t, err)
+		asset, err := lock.asset(tool)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, asset, &lockedAsset{Tag: "v1.0.0", Asset: "tool", ArchiveSHA256: "a", BinarySHA256: "b"})
+
+		modifyRuntime(t, "darwin", "arm64")
+		asset, err = lock.asset(tool)
+		assert.NilError(t, err)
+		assert.Assert(t, asset == nil)
+	})
+
+	t.Run("Rejects stale entries", func(t *testing.T) {
+		lock := newLockFile("")
+		lock.set(&bin{Name: "tool", Version: "1.0.0"}, &lockedAsset{})
+
+		_, err := lock.asset(&bin{Name: "tool", Version: "1.1.0"})
+		assert.Error(t, err, "lock file is out of date (locked \"1.0.0\", configured \"1.1.0\"); run `bine lock`")
+	})
+
+	t.Run("Prunes unconfigured bins", func(t *testing.T) {
+		lock := newLockFile("")
+		lock.set(&bin{Name: "tool", Version: "1.0.0"}, &lockedAsset{})
+		lock.set(&bin{Name: "gone", Version: "1.0.0"}, &lockedAsset{})
+		lock.set(&bin{Name: "unpinned", Version: "1.0.0"}, &lockedAsset{})
+
+		lock.prune([]*bin{{Name: "tool"}, {Name: "unpinned", Version: "latest"}})
+
+		assert.Equal(t, len(lock.Bins), 1)
+		assert.Assert(t, lock.Bins["tool"] != nil)
+	})
+}
+
+func TestLock(t *testing.T) {
+	assets := map[string]string{"/tool_1.0.0": "binary-1"}
+	b, tool := newLockTestBine(t, assets)
+
+	err := b.Lock(t.Context())
+	assert.NilError(t, err)
+
+	lock, err := loadLockFile(lockFilePath(b.config.path))
+	assert.NilError(t, err)
+	asset, err := lock.asset(tool)
+	assert.NilError(t, err)
+	assert.Equal(t, asset.Tag, "v1.0.0")
+	assert.Equal(t, asset.Asset, "tool_1.0.0")
+	assert.Equal(t, asset.ArchiveSHA256, sha256Hex("binary-1"))
+	assert.Equal(t, asset.BinarySHA256, sha256Hex("binary-1"))
+
+	// Installs when the download matches the lock file.
+	path, err := b.GetForce(t.Context(), tool.Name)
+	assert.NilError(t, err)
+
+	// Refuses to install a different artifact.
+	assets["/tool_1.0.0"] = "binary-2"
+	_, err = b.GetForce(t.Context(), tool.Name)
+	assert.ErrorContains(t, err, "archive checksum mismatch")
+
+	blob, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(blob), "binary-1")
+}
+
+func TestInstalledReinstallsBinariesNotMatchingLock(t *testing.T) {
+	assets := map[string]string{"/tool_1.0.0": "binary-1"}
+	b, tool := newLockTestBine(t, assets)
+
+	_, err := b.Get(t.Context(), tool.Name)
+	assert.NilError(t, err)
+
+	b.config.lock = newLockFile(lockFilePath(b.config.path))
+	b.config.lock.set(tool, &lockedAsset{
+		ArchiveSHA256: sha256Hex("binary-2"),
+		BinarySHA256:  sha256Hex("binary-2"),
+	})
+
+	ok, err := b.installed(t.Context(), tool)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+
+	_, err = b.Get(t.Context(), tool.Name)
+	assert.ErrorContains(t, err, "archive checksum mismatch")
+}
+
+func TestLockGoPackage(t *testing.T) {
+	injectFakeExec(t, "TestHelperProcessGoInstallWithSum")
+	t.Setenv("BINE_HELPER_SUM", "h1:abc=")
+
+	b, _ := newLockTestBine(t, nil)
+	tool := &bin{
+		Name:      "tool",
+		GoPackage: "github.com/foo/bar/cmd/tool",
+		Version:   "1.0.0",
+	}
+	b.config.Bins = []*bin{tool}
+
+	err := b.Lock(t.Context())
+	assert.NilError(t, err)
+
+	lock, err := loadLockFile(lockFilePath(b.config.path))
+	assert.NilError(t, err)
+	sum, err := lock.moduleSum(tool)
+	assert.NilError(t, err)
+	assert.Equal(t, sum, "h1:abc=")
+
+	marker, err := b.readVersionMarker(tool)
+	assert.NilError(t, err)
+	assert.Equal(t, marker.ModuleSum, "h1:abc=")
+
+	ok, err := b.installed(t.Context(), tool)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+
+	// Refuses to install a module with a different hash.
+	t.Setenv("BINE_HELPER_SUM", "h1:xyz=")
+	_, err = b.GetForce(t.Context(), tool.Name)
+	assert.ErrorContains(t, err, "module sum mismatch: lock file has h1:abc=, installed h1:xyz=")
+
+	_, err = os.Stat(filepath.Join(b.BinDir, tool.Name))
+	assert.Assert(t, os.IsNotExist(err))
+
+	// Reinstalls binaries whose marker doesn't match the lock file.
+	t.Setenv("BINE_HELPER_SUM", "h1:abc=")
+	_, err = b.GetForce(t.Context(), tool.Name)
+	assert.NilError(t, err)
+	b.config.lock.setModuleSum(tool, "h1:xyz=")
+
+	ok, err = b.installed(t.Context(), tool)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+}
@@ -60,17 +60,52 @@ func (n *namer) run(bins []*bin) {
 		if b.goPkg() {
 			continue
 		}
-		asset := b.AssetPattern
-		asset = strings.ReplaceAll(asset, "{name}", b.Name)
-		asset = strings.ReplaceAll(asset, "{version}", b.unprefixedVersion())
-		asset = strings.ReplaceAll(asset, "{goos}", n.applyModifier(b, "goos", goos))
-		asset = strings.ReplaceAll(asset, "{goarch}", n.applyModifier(b, "goarch", goarch))
-		asset = strings.ReplaceAll(asset, "{os}", n.applyModifier(b, "os", n.unameOS))
-		asset = strings.ReplaceAll(asset, "{arch}", n.applyModifier(b, "arch", n.unameArch))
-		asset = strings.ReplaceAll(asset, "{triple}", n.triple)
-
-		b.asset = asset
+		b.asset = n.expand(b, b.assetPattern())
+		b.autoAsset = nil
+		if b.assetPattern() == "" {
+			b.autoAsset, _ = n.assetPlatform(goos + "/" + goarch)
+		}
+		b.expandedDownloadURL = n.expand(b, b.DownloadURL)
+		b.checksumAsset = n.expand(b, b.ChecksumPattern)
+		if b.Signature != nil {
+			b.signatureAsset = n.expand(b, b.Signature.Pattern)
+		}
+	}
+}
+
+// expand replaces the template variables found in pattern.
+func (n *namer) expand(b *bin, pattern string) string {
+	return n.expandVars(b, pattern, goos, goarch)
+}
+
+// expandPlatform is like expand but targets the given platform, e.g.
+// "darwin/arm64". Only {goos} and {goarch} can be computed for platforms other
+// than the current one.
+func (n *namer) expandPlatform(b *bin, pattern, platform string) (string, error) {
+	targetOS, targetArch, ok := strings.Cut(platform, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid platform %q", platform)
 	}
+	if targetOS != goos || targetArch != goarch {
+		for _, v := range []string{"{os}", "{arch}", "{triple}"} {
+			if strings.Contains(pattern, v) {
+				return "", fmt.Errorf("cannot expand %s for platform %q", v, platform)
+			}
+		}
+	}
+
+	return n.expandVars(b, pattern, targetOS, targetArch), nil
+}
+
+func (n *namer) expandVars(b *bin, pattern, goos, goarch string) string {
+	pattern = strings.ReplaceAll(pattern, "{name}", b.Name)
+	pattern = strings.ReplaceAll(pattern, "{version}", b.unprefixedVersion())
+	pattern = strings.ReplaceAll(pattern, "{goos}", n.applyModifier(b, "goos", goos))
+	pattern = strings.ReplaceAll(pattern, "{goarch}", n.applyModifier(b, "goarch", goarch))
+	pattern = strings.ReplaceAll(pattern, "{os}", n.applyModifier(b, "os", n.unameOS))
+	pattern = strings.ReplaceAll(pattern, "{arch}", n.applyModifier(b, "arch", n.unameArch))
+	pattern = strings.ReplaceAll(pattern, "{triple}", n.triple)
+	return pattern
 }
 
 // applyModifier applies template variable modifiers if they exist for the
@@ -65,3 +65,19 @@ func TestNamer(t *testing.T) {
 		t.Log(bins[0].asset)
 	})
 }
+
+func TestNamerExpandsChecksumPattern(t *testing.T) {
+	modifyRuntime(t, "linux", "amd64")
+
+	n := &namer{}
+	bins := []*bin{{
+		Name:            "tool",
+		Version:         "1.2.3",
+		AssetPattern:    "{name}_{version}_{goos}_{goarch}.tar.gz",
+		ChecksumPattern: "{name}_{version}_checksums.txt",
+	}}
+	n.run(bins)
+
+	assert.Equal(t, bins[0].asset, "tool_1.2.3_linux_amd64.tar.gz")
+	assert.Equal(t, bins[0].checksumAsset, "tool_1.2.3_checksums.txt")
+}
@@ -0,0 +1,553 @@
+package bine
+
+import (
+	"archive/tar"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Media types of the manifests served by OCI distribution registries.
+const (
+	ociIndexMediaType            = "application/vnd.oci.image.index.v1+json"
+	ociManifestMediaType         = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestListMediaType  = "application/vnd.docker.distribution.manifest.list.v2+json"
+	dockerManifestMediaType      = "application/vnd.docker.distribution.manifest.v2+json"
+	ociMaxSymlinks               = 10
+	ociDefaultRegistry           = "registry-1.docker.io"
+	ociDefaultRegistryRepository = "library"
+)
+
+// ociProvider extracts binaries from container images published on OCI
+// distribution registries, e.g. "ghcr.io/org/tool".
+type ociProvider struct {
+	client *http.Client
+
+	// baseURL of the registry, e.g. "https://ghcr.io".
+	baseURL string
+	// repository of the image in the registry, e.g. "org/tool".
+	repository string
+
+	// token is the bearer token obtained from the registry.
+	token string
+}
+
+var (
+	_ binProvider = &ociProvider{}
+	_ installer   = &ociProvider{}
+)
+
+// newOCIProvider parses the image reference found in the URL of the bin, e.g.
+// "ghcr.io/org/tool". References without a registry point to Docker Hub. Local
+// registries can be reached over plain HTTP with "http://localhost:5000/tool".
+func newOCIProvider(client *http.Client, b *bin) (*ociProvider, error) {
+	if b.URL == "" {
+		return nil, fmt.Errorf("url is required by the %q provider", providerOCI)
+	}
+
+	scheme, ref := "https", strings.TrimPrefix(b.URL, "oci://")
+	if after, ok := strings.CutPrefix(ref, "http://"); ok {
+		scheme, ref = "http", after
+	} else if after, ok := strings.CutPrefix(ref, "https://"); ok {
+		ref = after
+	}
+	ref = strings.Trim(ref, "/")
+
+	host, repository, ok := strings.Cut(ref, "/")
+	if !ok || !strings.ContainsAny(host, ".:") && host != "localhost" {
+		host, repository = ociDefaultRegistry, ref
+		if !strings.Contains(repository, "/") {
+			repository = ociDefaultRegistryRepository + "/" + repository
+		}
+	} else if host == "docker.io" {
+		host = ociDefaultRegistry
+	}
+	if repository == "" || strings.ContainsAny(repository, ":@") {
+		return nil, fmt.Errorf("invalid image reference %q, the tag is set by tag_pattern", b.URL)
+	}
+
+	return &ociProvider{
+		client:     client,
+		baseURL:    scheme + "://" + host,
+		repository: repository,
+	}, nil
+}
+
+// downloadURL returns no URL so the binary is extracted by install.
+func (p *ociProvider) downloadURL(context.Context, *bin) (string, error) {
+	return "", nil
+}
+
+func (p *ociProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
+	var tags []string
+	next := fmt.Sprintf("%s/v2/%s/tags/list?n=1000", p.baseURL, p.repository)
+	for next != "" {
+		resp, err := p.get(ctx, next, "application/json")
+		if err != nil {
+			return "", err
+		}
+		var list struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		link := resp.Header.Get("Link")
+		_ = resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to decode tags list: %v", err)
+		}
+
+		tags = append(tags, list.Tags...)
+
+		next, err = nextPage(next, link)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	latestVersion := latestTaggedVersion(bin, tags)
+	if latestVersion == "" {
+		return "", fmt.Errorf("no valid %s tags found in registry matching tag pattern", bin.versionKind())
+	}
+
+	return latestVersion, nil
+}
+
+type ociDescriptor struct {
+	MediaType string       `json:"mediaType"`
+	Digest    string       `json:"digest"`
+	Platform  *ociPlatform `json:"platform,omitempty"`
+}
+
+type ociPlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+func (p ociPlatform) String() string {
+	if p.Variant == "" {
+		return p.OS + "/" + p.Architecture
+	}
+	return p.OS + "/" + p.Architecture + "/" + p.Variant
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+	Config    *ociDescriptor  `json:"config,omitempty"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// install extracts the binary found at the image path of the bin from the
+// image built for the current platform.
+func (p *ociProvider) install(ctx context.Context, b *bin, binPath string) (string, error) {
+	manifest, err := p.manifest(ctx, b.tag())
+	if err != nil {
+		return "", err
+	}
+
+	if len(manifest.Manifests) > 0 {
+		digest, err := platformManifest(manifest.Manifests)
+		if err != nil {
+			return "", fmt.Errorf("image %s:%s %v", p.repository, b.tag(), err)
+		}
+		if manifest, err = p.manifest(ctx, digest); err != nil {
+			return "", err
+		}
+	} else {
+		// Single-platform images are only described by their configuration.
+		platform, err := p.platform(ctx, manifest.Config)
+		if err != nil {
+			return "", err
+		}
+		if !platform.matches() {
+			return "", fmt.Errorf("image %s:%s is built for %s, not for %s", p.repository, b.tag(), platform, currentPlatform())
+		}
+	}
+
+	target := path.Clean("/" + b.imagePath())
+	for range ociMaxSymlinks {
+		found, link, err := p.extract(ctx, manifest.Layers, target, binPath)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return "", nil
+		}
+		if link == "" {
+			return "", fmt.Errorf("%s not found in image %s:%s", target, p.repository, b.tag())
+		}
+		target = link
+	}
+
+	return "", fmt.Errorf("too many links resolving %s", b.imagePath())
+}
+
+// platformManifest returns the digest of the manifest of the index built for
+// the current platform. On arm, the variant must match GOARM, v7 by default,
+// unless a single image is available without variant.
+func platformManifest(manifests []ociDescriptor) (string, error) {
+	var candidates []ociDescriptor
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.OS == goos && m.Platform.Architecture == goarch {
+			candidates = append(candidates, m)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("is not available for %s/%s", goos, goarch)
+	}
+	if goarch != "arm" {
+		return candidates[0].Digest, nil
+	}
+
+	variant := goarmVariant()
+	for _, m := range candidates {
+		if m.Platform.Variant == variant {
+			return m.Digest, nil
+		}
+	}
+	if len(candidates) == 1 && candidates[0].Platform.Variant == "" {
+		return candidates[0].Digest, nil
+	}
+
+	var available []string
+	for _, m := range candidates {
+		available = append(available, m.Platform.String())
+	}
+	slices.Sort(available)
+	return "", fmt.Errorf("is not available for %s/%s/%s (%s), set GOARM", goos, goarch, variant, strings.Join(available, ", "))
+}
+
+// goarmVariant returns the arm variant of the current platform, following
+// GOARM like the Go toolchain does.
+func goarmVariant() string {
+	goarm, _, _ := strings.Cut(os.Getenv("GOARM"), ",")
+	return "v" + cmp.Or(goarm, "7")
+}
+
+// currentPlatform describes the current platform in error messages.
+func currentPlatform() string {
+	if goarch == "arm" {
+		return goos + "/" + goarch + "/" + goarmVariant()
+	}
+	return goos + "/" + goarch
+}
+
+// matches reports whether the platform is the current one. On arm, images
+// without variant are accepted like in platformManifest.
+func (p ociPlatform) matches() bool {
+	if p.OS != goos || p.Architecture != goarch {
+		return false
+	}
+	return goarch != "arm" || p.Variant == "" || p.Variant == goarmVariant()
+}
+
+// extract looks for target in the layers, starting with the top one, and
+// writes it to binPath. If target is a link, it returns the path it points to.
+func (p *ociProvider) extract(ctx context.Context, layers []ociDescriptor, target, binPath string) (bool, string, error) {
+	for i := len(layers) - 1; i >= 0; i-- {
+		found, link, hidden, err := p.extractLayer(ctx, layers[i], target, binPath)
+		if err != nil || found || link != "" {
+			return found, link, err
+		}
+		if hidden {
+			break
+		}
+	}
+
+	return false, "", nil
+}
+
+// extractLayer looks for target in a single layer. hidden is true when the
+// layer deletes target from the layers below it. The whole layer is read to
+// check it against its digest, even when target comes first.
+func (p *ociProvider) extractLayer(ctx context.Context, layer ociDescriptor, target, binPath string) (found bool, link string, hidden bool, err error) {
+	digest, err := newOCIDigest(layer.Digest)
+	if err != nil {
+		return false, "", false, fmt.Errorf("layer %s: %v", layer.Digest, err)
+	}
+	resp, err := p.get(ctx, fmt.Sprintf("%s/v2/%s/blobs/%s", p.baseURL, p.repository, layer.Digest), "*/*")
+	if err != nil {
+		return false, "", false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body := io.TeeReader(resp.Body, digest.hash)
+	found, link, hidden, err = p.scanLayer(body, layer, target, binPath)
+	if err == nil {
+		if _, err = io.Copy(io.Discard, body); err != nil {
+			err = fmt.Errorf("layer %s: %v", layer.Digest, err)
+		} else if err = digest.verify(); err != nil {
+			err = fmt.Errorf("layer %s: %v", layer.Digest, err)
+		}
+	}
+	if err != nil {
+		if found {
+			_ = os.Remove(binPath)
+		}
+		return false, "", false, err
+	}
+
+	return found, link, hidden, nil
+}
+
+// scanLayer reads the entries of a layer until it finds target, see
+// extractLayer.
+func (p *ociProvider) scanLayer(blob io.Reader, layer ociDescriptor, target, binPath string) (found bool, link string, hidden bool, err error) {
+	var r io.Reader = blob
+	switch {
+	case strings.HasSuffix(layer.MediaType, "gzip"):
+		gz, err := gzip.NewReader(blob)
+		if err != nil {
+			return false, "", false, fmt.Errorf("layer %s: %v", layer.Digest, err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	case strings.HasSuffix(layer.MediaType, ".tar"):
+	default:
+		return false, "", false, fmt.Errorf("layer %s: unsupported media type %q", layer.Digest, layer.MediaType)
+	}
+
+	dir, name := path.Split(target)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return false, "", hidden, nil
+		} else if err != nil {
+			return false, "", false, fmt.Errorf("layer %s: %v", layer.Digest, err)
+		}
+
+		entry := path.Clean("/" + hdr.Name)
+		entryDir, entryName := path.Split(entry)
+		switch {
+		case entry == path.Join(dir, ".wh."+name):
+			hidden = true
+			continue
+		case entryName == ".wh..wh..opq" && strings.HasPrefix(target, entryDir):
+			hidden = true
+			continue
+		case entry != target:
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			f, err := os.Create(binPath)
+			if err != nil {
+				return false, "", false, err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				_ = f.Close()
+				return false, "", false, fmt.Errorf("layer %s: %v", layer.Digest, err)
+			}
+			return true, "", false, f.Close()
+		case tar.TypeSymlink:
+			if path.IsAbs(hdr.Linkname) {
+				return false, path.Clean(hdr.Linkname), false, nil
+			}
+			return false, path.Join(dir, hdr.Linkname), false, nil
+		case tar.TypeLink:
+			return false, path.Clean("/" + hdr.Linkname), false, nil
+		default:
+			return false, "", false, fmt.Errorf("%s is not a regular file", target)
+		}
+	}
+}
+
+// manifest retrieves the manifest, or the index, of the given tag or digest.
+// Manifests retrieved by digest are checked against it.
+func (p *ociProvider) manifest(ctx context.Context, reference string) (*ociManifest, error) {
+	var digest *ociDigest
+	if strings.Contains(reference, ":") { // Tags can't contain colons.
+		var err error
+		if digest, err = newOCIDigest(reference); err != nil {
+			return nil, fmt.Errorf("manifest %s: %v", reference, err)
+		}
+	}
+
+	accept := strings.Join([]string{ociIndexMediaType, dockerManifestListMediaType, ociManifestMediaType, dockerManifestMediaType}, ", ")
+	resp, err := p.get(ctx, fmt.Sprintf("%s/v2/%s/manifests/%s", p.baseURL, p.repository, reference), accept)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	blob, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %v", err)
+	}
+	if digest != nil {
+		digest.hash.Write(blob)
+		if err := digest.verify(); err != nil {
+			return nil, fmt.Errorf("manifest %s: %v", reference, err)
+		}
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(blob, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %v", err)
+	}
+
+	return &manifest, nil
+}
+
+// platform retrieves the platform of an image from its configuration,
+// checked against its digest.
+func (p *ociProvider) platform(ctx context.Context, config *ociDescriptor) (*ociPlatform, error) {
+	if config == nil {
+		return nil, errors.New("image manifest has no config")
+	}
+	digest, err := newOCIDigest(config.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %v", config.Digest, err)
+	}
+	resp, err := p.get(ctx, fmt.Sprintf("%s/v2/%s/blobs/%s", p.baseURL, p.repository, config.Digest), "*/*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	blob, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %v", err)
+	}
+	digest.hash.Write(blob)
+	if err := digest.verify(); err != nil {
+		return nil, fmt.Errorf("config %s: %v", config.Digest, err)
+	}
+
+	var platform ociPlatform
+	if err := json.Unmarshal(blob, &platform); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %v", err)
+	}
+
+	return &platform, nil
+}
+
+// ociDigest checks content against a digest such as "sha256:<hex>".
+type ociDigest struct {
+	algorithm, value string
+	hash             hash.Hash
+}
+
+func newOCIDigest(digest string) (*ociDigest, error) {
+	algorithm, value, _ := strings.Cut(digest, ":")
+	alg, ok := pinnedChecksumAlgorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported digest %q", digest)
+	}
+	return &ociDigest{algorithm: algorithm, value: value, hash: alg.New()}, nil
+}
+
+// verify compares the digest of the content written to the hash.
+func (d *ociDigest) verify() error {
+	if actual := hex.EncodeToString(d.hash.Sum(nil)); actual != d.value {
+		return fmt.Errorf("digest mismatch: expected %s:%s, got %s:%s", d.algorithm, d.value, d.algorithm, actual)
+	}
+	return nil
+}
+
+// get sends a request to the registry. It obtains an anonymous bearer token
+// when the registry asks for one, which most public registries do.
+func (p *ociProvider) get(ctx context.Context, url, accept string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %v", err)
+		}
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Accept", accept)
+		if p.token != "" {
+			req.Header.Set("Authorization", "Bearer "+p.token)
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("send request: %v", err)
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			_ = resp.Body.Close()
+			if err := p.authenticate(ctx, challenge); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("registry returned status %d (%s)", resp.StatusCode, url)
+		}
+
+		return resp, nil
+	}
+}
+
+var ociChallengeRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate obtains a token following a Bearer challenge of the registry.
+func (p *ociProvider) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("unsupported registry authentication %q", scheme)
+	}
+
+	values := url.Values{}
+	var realm string
+	for _, match := range ociChallengeRegex.FindAllStringSubmatch(params, -1) {
+		switch match[1] {
+		case "realm":
+			realm = match[2]
+		case "service", "scope":
+			values.Set(match[1], match[2])
+		}
+	}
+	if realm == "" {
+		return errors.New("registry authentication challenge has no realm")
+	}
+	if values.Get("scope") == "" {
+		values.Set("scope", fmt.Sprintf("repository:%s:pull", p.repository))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+values.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token service returned status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode registry token: %v", err)
+	}
+	p.token = token.Token
+	if p.token == "" {
+		p.token = token.AccessToken
+	}
+
+	return nil
+}
@@ -0,0 +1,436 @@
+package bine
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// ociTestEntry is a file of a layer; whiteouts are files named ".wh.<name>".
+type ociTestEntry struct {
+	name     string
+	contents string
+	linkname string
+}
+
+func ociTestLayer(t *testing.T, entries ...ociTestEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o755, Typeflag: tar.TypeReg, Size: int64(len(e.contents))}
+		if e.linkname != "" {
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.linkname, 0
+		}
+		assert.NilError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(e.contents))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+// ociTestRegistry serves the images keyed by tag, each made of the layers
+// keyed by platform, and requires anonymous bearer tokens like ghcr.io does.
+type ociTestRegistry struct {
+	t         *testing.T
+	server    *httptest.Server
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	tags      []string
+}
+
+func newOCITestRegistry(t *testing.T, tags []string) *ociTestRegistry {
+	t.Helper()
+
+	r := &ociTestRegistry{t: t, blobs: map[string][]byte{}, manifests: map[string][]byte{}, tags: tags}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func (r *ociTestRegistry) add(blob []byte) string {
+	sum := sha256.Sum256(blob)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	r.blobs[digest] = blob
+	return digest
+}
+
+func (r *ociTestRegistry) image(tag string, platforms map[string][][]byte) {
+	var index ociManifest
+	index.MediaType = ociIndexMediaType
+	for platform, layers := range platforms {
+		var manifest ociManifest
+		manifest.MediaType = ociManifestMediaType
+		for _, layer := range layers {
+			manifest.Layers = append(manifest.Layers, ociDescriptor{
+				MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+				Digest:    r.add(layer),
+			})
+		}
+		blob, err := json.Marshal(manifest)
+		assert.NilError(r.t, err)
+		digest := r.add(blob)
+		r.manifests[digest] = blob
+
+		index.Manifests = append(index.Manifests, ociDescriptor{
+			MediaType: ociManifestMediaType,
+			Digest:    digest,
+			Platform:  ociTestPlatform(platform),
+		})
+	}
+	blob, err := json.Marshal(index)
+	assert.NilError(r.t, err)
+	r.manifests[tag] = blob
+}
+
+// singleImage serves a single-platform image, without index, whose platform
+// is only found in its config.
+func (r *ociTestRegistry) singleImage(tag, platform string, layers ...[]byte) {
+	config, err := json.Marshal(ociTestPlatform(platform))
+	assert.NilError(r.t, err)
+
+	var manifest ociManifest
+	manifest.MediaType = ociManifestMediaType
+	manifest.Config = &ociDescriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: r.add(config)}
+	for _, layer := range layers {
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+			Digest:    r.add(layer),
+		})
+	}
+	blob, err := json.Marshal(manifest)
+	assert.NilError(r.t, err)
+	r.manifests[tag] = blob
+}
+
+// ociTestPlatform parses platforms such as "linux/arm/v7".
+func ociTestPlatform(platform string) *ociPlatform {
+	p := &ociPlatform{}
+	p.OS, p.Architecture, _ = strings.Cut(platform, "/")
+	p.Architecture, p.Variant, _ = strings.Cut(p.Architecture, "/")
+	return p
+}
+
+func (r *ociTestRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		assert.Equal(r.t, req.URL.Query().Get("scope"), "repository:org/tool:pull")
+		_, _ = w.Write([]byte(`{"token": "anonymous"}`))
+		return
+	}
+	if req.Header.Get("Authorization") != "Bearer anonymous" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:org/tool:pull"`, r.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path, ok := strings.CutPrefix(req.URL.Path, "/v2/org/tool/")
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	switch {
+	case path == "tags/list":
+		// Serve the tags in pages of two.
+		page := r.tags
+		if last := req.URL.Query().Get("last"); last != "" {
+			for i, tag := range r.tags {
+				if tag == last {
+					page = r.tags[i+1:]
+				}
+			}
+		}
+		if len(page) > 2 {
+			page = page[:2]
+			w.Header().Set("Link", fmt.Sprintf(`</v2/org/tool/tags/list?n=2&last=%s>; rel="next"`, page[1]))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": "org/tool", "tags": page})
+	case strings.HasPrefix(path, "manifests/"):
+		blob, ok := r.manifests[strings.TrimPrefix(path, "manifests/")]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write(blob)
+	case strings.HasPrefix(path, "blobs/"):
+		blob, ok := r.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write(blob)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func TestOCIProvider(t *testing.T) {
+	registry := newOCITestRegistry(t, []string{"0.9.0", "1.0.0", "latest", "1.1.0", "2.0.0-rc.1"})
+	registry.image("1.0.0", map[string][][]byte{
+		"linux/amd64": {
+			ociTestLayer(t, ociTestEntry{name: "usr/local/bin/tool", contents: "base"}),
+			ociTestLayer(t,
+				ociTestEntry{name: "opt/tool/bin/tool", contents: "tool linux/amd64"},
+				ociTestEntry{name: "usr/local/bin/tool", linkname: "../../../opt/tool/bin/tool"},
+			),
+		},
+		"linux/arm64": {
+			ociTestLayer(t, ociTestEntry{name: "usr/local/bin/tool", contents: "tool linux/arm64"}),
+		},
+	})
+	registry.image("1.1.0", map[string][][]byte{
+		"linux/amd64": {
+			ociTestLayer(t, ociTestEntry{name: "usr/local/bin/tool", contents: "base"}),
+			ociTestLayer(t, ociTestEntry{name: "usr/local/bin/.wh.tool"}),
+		},
+	})
+
+	newOCITestBine := func(t *testing.T) (*Bine, *bin) {
+		t.Helper()
+
+		b, tool := newLockTestBine(t, nil)
+		b.client = registry.server.Client()
+		tool.URL = registry.server.URL + "/org/tool"
+		tool.Provider = providerOCI
+		tool.TagPattern = "{version}"
+		tool.provider = nil
+		assert.NilError(t, tool.loadProvider(providerOptions{client: b.client}))
+
+		return b, tool
+	}
+
+	t.Run("latestVersion follows pages and skips prereleases", func(t *testing.T) {
+		_, tool := newOCITestBine(t)
+
+		latest, err := tool.provider.latestVersion(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.1.0")
+	})
+
+	t.Run("Extracts the binary of the platform", func(t *testing.T) {
+		for _, platform := range []string{"linux/amd64", "linux/arm64"} {
+			goos, goarch, _ := strings.Cut(platform, "/")
+			modifyRuntime(t, goos, goarch)
+			b, tool := newOCITestBine(t)
+
+			binPath, err := b.Get(t.Context(), "tool")
+			assert.NilError(t, err)
+
+			blob, err := os.ReadFile(binPath)
+			assert.NilError(t, err)
+			assert.Equal(t, string(blob), "tool "+platform)
+
+			marker, err := b.readVersionMarker(tool)
+			assert.NilError(t, err)
+			assert.Equal(t, marker.Checksum.Value, sha256Hex("tool "+platform))
+		}
+	})
+
+	t.Run("Fails when the platform is not available", func(t *testing.T) {
+		modifyRuntime(t, "darwin", "arm64")
+		b, _ := newOCITestBine(t)
+
+		_, err := b.Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, "image org/tool:1.0.0 is not available for darwin/arm64")
+	})
+
+	t.Run("Honors whiteouts", func(t *testing.T) {
+		modifyRuntime(t, "linux", "amd64")
+		b, tool := newOCITestBine(t)
+		tool.Version = "1.1.0"
+
+		_, err := b.Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, "/usr/local/bin/tool not found in image org/tool:1.1.0")
+	})
+
+	t.Run("Fails when the image path does not exist", func(t *testing.T) {
+		modifyRuntime(t, "linux", "arm64")
+		b, tool := newOCITestBine(t)
+		tool.ImagePath = "/bin/{name}"
+
+		_, err := b.Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, "/bin/tool not found in image org/tool:1.0.0")
+	})
+}
+
+func TestOCIProviderDigests(t *testing.T) {
+	registry := newOCITestRegistry(t, []string{"1.0.0"})
+	registry.image("1.0.0", map[string][][]byte{
+		"linux/amd64": {
+			ociTestLayer(t, ociTestEntry{name: "usr/local/bin/tool", contents: "tool"}, ociTestEntry{name: "etc/motd", contents: "hi"}),
+		},
+	})
+	modifyRuntime(t, "linux", "amd64")
+
+	newOCITestBine := func(t *testing.T) *Bine {
+		t.Helper()
+
+		b, tool := newLockTestBine(t, nil)
+		b.client = registry.server.Client()
+		tool.URL = registry.server.URL + "/org/tool"
+		tool.Provider = providerOCI
+		tool.TagPattern = "{version}"
+		tool.provider = nil
+		assert.NilError(t, tool.loadProvider(providerOptions{client: b.client}))
+
+		return b
+	}
+
+	t.Run("Rejects layers not matching their digest", func(t *testing.T) {
+		for digest, blob := range registry.blobs {
+			if _, ok := registry.manifests[digest]; !ok {
+				registry.blobs[digest] = append(slices.Clone(blob[:len(blob)-1]), blob[len(blob)-1]^0xff)
+				defer func() { registry.blobs[digest] = blob }()
+			}
+		}
+
+		_, err := newOCITestBine(t).Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, "digest mismatch")
+	})
+
+	t.Run("Rejects manifests not matching their digest", func(t *testing.T) {
+		for digest, blob := range registry.manifests {
+			if strings.HasPrefix(digest, "sha256:") {
+				registry.manifests[digest] = append(slices.Clone(blob[:len(blob)-1]), ' ', '}')
+				defer func() { registry.manifests[digest] = blob }()
+			}
+		}
+
+		_, err := newOCITestBine(t).Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, "digest mismatch")
+	})
+
+	t.Run("Accepts matching digests", func(t *testing.T) {
+		_, err := newOCITestBine(t).Get(t.Context(), "tool")
+		assert.NilError(t, err)
+	})
+}
+
+func TestOCIProviderSingleImage(t *testing.T) {
+	registry := newOCITestRegistry(t, []string{"1.0.0"})
+	registry.singleImage("1.0.0", "linux/arm64", ociTestLayer(t, ociTestEntry{name: "usr/local/bin/tool", contents: "tool linux/arm64"}))
+
+	newOCITestBine := func(t *testing.T) *Bine {
+		t.Helper()
+
+		b, tool := newLockTestBine(t, nil)
+		b.client = registry.server.Client()
+		tool.URL = registry.server.URL + "/org/tool"
+		tool.Provider = providerOCI
+		tool.TagPattern = "{version}"
+		tool.provider = nil
+		assert.NilError(t, tool.loadProvider(providerOptions{client: b.client}))
+
+		return b
+	}
+
+	t.Run("Extracts the binary of the platform", func(t *testing.T) {
+		modifyRuntime(t, "linux", "arm64")
+
+		binPath, err := newOCITestBine(t).Get(t.Context(), "tool")
+		assert.NilError(t, err)
+
+		blob, err := os.ReadFile(binPath)
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), "tool linux/arm64")
+	})
+
+	t.Run("Rejects images of other platforms", func(t *testing.T) {
+		modifyRuntime(t, "linux", "amd64")
+
+		_, err := newOCITestBine(t).Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, "image org/tool:1.0.0 is built for linux/arm64, not for linux/amd64")
+	})
+}
+
+func TestOCIPlatformManifest(t *testing.T) {
+	index := func(platforms ...string) []ociDescriptor {
+		var manifests []ociDescriptor
+		for _, platform := range platforms {
+			manifests = append(manifests, ociDescriptor{Digest: platform, Platform: ociTestPlatform(platform)})
+		}
+		return manifests
+	}
+
+	tests := []struct {
+		name      string
+		goarch    string
+		goarm     string
+		platforms []string
+		want      string
+		err       string
+	}{
+		{name: "Matches the architecture", goarch: "amd64", platforms: []string{"linux/arm64", "linux/amd64"}, want: "linux/amd64"},
+		{name: "Defaults to arm v7", goarch: "arm", platforms: []string{"linux/arm/v6", "linux/arm/v7"}, want: "linux/arm/v7"},
+		{name: "Follows GOARM", goarch: "arm", goarm: "6", platforms: []string{"linux/arm/v7", "linux/arm/v6"}, want: "linux/arm/v6"},
+		{name: "Ignores the float ABI of GOARM", goarch: "arm", goarm: "6,softfloat", platforms: []string{"linux/arm/v6"}, want: "linux/arm/v6"},
+		{name: "Accepts a single image without variant", goarch: "arm", platforms: []string{"linux/arm"}, want: "linux/arm"},
+		{
+			name:      "Rejects other variants",
+			goarch:    "arm",
+			platforms: []string{"linux/arm/v8", "linux/arm/v6"},
+			err:       "is not available for linux/arm/v7 (linux/arm/v6, linux/arm/v8), set GOARM",
+		},
+		{name: "Rejects other platforms", goarch: "riscv64", platforms: []string{"linux/amd64"}, err: "is not available for linux/riscv64"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifyRuntime(t, "linux", tc.goarch)
+			t.Setenv("GOARM", tc.goarm)
+
+			digest, err := platformManifest(index(tc.platforms...))
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, digest, tc.want)
+		})
+	}
+}
+
+func TestNewOCIProvider(t *testing.T) {
+	tests := []struct {
+		url        string
+		baseURL    string
+		repository string
+		err        string
+	}{
+		{url: "ghcr.io/org/tool", baseURL: "https://ghcr.io", repository: "org/tool"},
+		{url: "oci://quay.io/org/team/tool", baseURL: "https://quay.io", repository: "org/team/tool"},
+		{url: "http://localhost:5000/tool", baseURL: "http://localhost:5000", repository: "tool"},
+		{url: "alpine", baseURL: "https://registry-1.docker.io", repository: "library/alpine"},
+		{url: "docker.io/hashicorp/terraform", baseURL: "https://registry-1.docker.io", repository: "hashicorp/terraform"},
+		{url: "ghcr.io/org/tool:1.0.0", err: `invalid image reference "ghcr.io/org/tool:1.0.0", the tag is set by tag_pattern`},
+		{url: "", err: `url is required by the "oci" provider`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			provider, err := newOCIProvider(http.DefaultClient, &bin{Name: "tool", URL: tc.url})
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, provider.baseURL, tc.baseURL)
+			assert.Equal(t, provider.repository, tc.repository)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package bine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// pluginPrefix is the prefix of the executables implementing external
+// providers, e.g. "bine-provider-s3" implements provider = "s3".
+const pluginPrefix = "bine-provider-"
+
+var pluginKindRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Methods of the plugin protocol.
+const (
+	pluginMethodLatestVersion = "latest_version"
+	pluginMethodDownloadURL   = "download_url"
+	pluginMethodInstall       = "install"
+)
+
+// pluginRequest is written as JSON to the standard input of the plugin.
+type pluginRequest struct {
+	Method string    `json:"method"`
+	Bin    pluginBin `json:"bin"`
+	// Path where the binary must be written, only set by "install".
+	Path string `json:"path,omitempty"`
+}
+
+// pluginBin describes the bin to the plugin.
+type pluginBin struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	URL     string            `json:"url,omitempty"`
+	Tag     string            `json:"tag"`
+	Asset   string            `json:"asset,omitempty"`
+	GOOS    string            `json:"goos"`
+	GOARCH  string            `json:"goarch"`
+	Options map[string]string `json:"options,omitempty"`
+	// Prereleases is set when "latest_version" may reply with a prerelease.
+	Prereleases bool `json:"prereleases,omitempty"`
+}
+
+// pluginResponse is read as JSON from the standard output of the plugin.
+type pluginResponse struct {
+	// Version is the reply to "latest_version".
+	Version string `json:"version,omitempty"`
+	// URL is the reply to "download_url". When empty, bine asks the plugin
+	// to install the binary itself.
+	URL string `json:"url,omitempty"`
+	// Commit is the revision the binary was built from, optionally reported
+	// by "install".
+	Commit string `json:"commit,omitempty"`
+	// Error reports a failure of any method.
+	Error string `json:"error,omitempty"`
+}
+
+// pluginProvider delegates to an external bine-provider-<kind> executable
+// speaking JSON over stdio. bine still verifies and records what the plugin
+// downloads or installs.
+type pluginProvider struct {
+	kind string
+
+	// locate returns the path of the plugin executable. It defaults to
+	// looking up the plugin in PATH.
+	locate func(ctx context.Context, name string) (string, error)
+}
+
+var (
+	_ binProvider = &pluginProvider{}
+	_ installer   = &pluginProvider{}
+)
+
+func newPluginProvider(kind string) (*pluginProvider, error) {
+	if !pluginKindRegex.MatchString(kind) {
+		return nil, fmt.Errorf("invalid provider name %q", kind)
+	}
+
+	return &pluginProvider{kind: kind}, nil
+}
+
+func (p *pluginProvider) downloadURL(ctx context.Context, b *bin) (string, error) {
+	resp, err := p.call(ctx, b, pluginMethodDownloadURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	return resp.URL, nil
+}
+
+func (p *pluginProvider) latestVersion(ctx context.Context, b *bin) (string, error) {
+	resp, err := p.call(ctx, b, pluginMethodLatestVersion, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.Version == "" {
+		return "", fmt.Errorf("%s%s returned no version", pluginPrefix, p.kind)
+	}
+	// The range is sent as the version of the bin.
+	if b.isRange() && !b.constraint.check(resp.Version) {
+		return "", fmt.Errorf("%s%s returned version %s, which is out of range %s", pluginPrefix, p.kind, resp.Version, b.Version)
+	}
+
+	return strings.TrimPrefix(resp.Version, "v"), nil
+}
+
+func (p *pluginProvider) install(ctx context.Context, b *bin, binPath string) (string, error) {
+	resp, err := p.call(ctx, b, pluginMethodInstall, binPath)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Commit, nil
+}
+
+// call runs the plugin with the request and decodes its response.
+func (p *pluginProvider) call(ctx context.Context, b *bin, method, path string) (*pluginResponse, error) {
+	name := pluginPrefix + p.kind
+
+	locate := p.locate
+	if locate == nil {
+		locate = func(_ context.Context, name string) (string, error) {
+			return exec.LookPath(name)
+		}
+	}
+	command, err := locate(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find provider plugin %q: %v", name, err)
+	}
+
+	blob, err := json.Marshal(pluginRequest{
+		Method: method,
+		Bin: pluginBin{
+			Name:    b.Name,
+			Version: b.unprefixedVersion(),
+			URL:     b.URL,
+			Tag:     b.tag(),
+			Asset:   b.asset,
+			GOOS:    goos,
+			GOARCH:  goarch,
+			Options: b.ProviderOptions,
+
+			Prereleases: b.prereleases(),
+		},
+		Path: path,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode plugin request: %v", err)
+	}
+
+	cmd := execCommand(ctx, command, method)
+	cmd.Stdin = bytes.NewReader(blob)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = "(no stderr output)"
+		}
+		return nil, fmt.Errorf("%s %s failed: %v\nstderr: %s", name, method, err, msg)
+	}
+
+	var resp pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("decode %s response: %v", name, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s %s: %s", name, method, resp.Error)
+	}
+
+	return &resp, nil
+}
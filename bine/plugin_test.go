@@ -0,0 +1,143 @@
+package bine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// TestHelperProcessPlugin implements a bine-provider-test plugin in tests. The
+// download URL is taken from BINE_HELPER_URL; without it, the plugin installs
+// the binary itself.
+func TestHelperProcessPlugin(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	var req pluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if req.Method != os.Args[len(os.Args)-1] {
+		os.Exit(3)
+	}
+
+	var resp pluginResponse
+	switch req.Method {
+	case pluginMethodLatestVersion:
+		if req.Bin.Options["channel"] == "broken" {
+			resp.Error = "channel is broken"
+		} else {
+			resp.Version = "v1.1.0"
+		}
+	case pluginMethodDownloadURL:
+		if url := os.Getenv("BINE_HELPER_URL"); url != "" {
+			resp.URL = url + "/" + req.Bin.Asset
+		}
+	case pluginMethodInstall:
+		blob := fmt.Sprintf("%s %s %s/%s", req.Bin.Name, req.Bin.Tag, req.Bin.GOOS, req.Bin.GOARCH)
+		if err := os.WriteFile(req.Path, []byte(blob), 0o600); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(4)
+		}
+	}
+
+	_ = json.NewEncoder(os.Stdout).Encode(resp)
+	os.Exit(0)
+}
+
+func newTestPluginProvider() *pluginProvider {
+	return &pluginProvider{
+		kind: "test",
+		locate: func(_ context.Context, name string) (string, error) {
+			return name, nil
+		},
+	}
+}
+
+func TestPluginProvider(t *testing.T) {
+	injectFakeExec(t, "TestHelperProcessPlugin")
+
+	t.Run("latestVersion", func(t *testing.T) {
+		provider := newTestPluginProvider()
+
+		latest, err := provider.latestVersion(t.Context(), &bin{Name: "tool", Version: "1.0.0"})
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.1.0")
+
+		_, err = provider.latestVersion(t.Context(), &bin{
+			Name:            "tool",
+			Version:         "1.0.0",
+			ProviderOptions: map[string]string{"channel": "broken"},
+		})
+		assert.Error(t, err, "bine-provider-test latest_version: channel is broken")
+	})
+
+	t.Run("Installs the downloaded asset", func(t *testing.T) {
+		b, tool := newLockTestBine(t, map[string]string{"/tool_1.0.0": "downloaded"})
+		t.Setenv("BINE_HELPER_URL", tool.provider.(assetServerProvider).baseURL)
+		tool.provider = newTestPluginProvider()
+
+		binPath, err := b.Get(t.Context(), "tool")
+		assert.NilError(t, err)
+
+		blob, err := os.ReadFile(binPath)
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), "downloaded")
+	})
+
+	t.Run("Lets the plugin install the binary", func(t *testing.T) {
+		modifyRuntime(t, "linux", "amd64")
+		t.Setenv("BINE_HELPER_URL", "")
+
+		b, tool := newLockTestBine(t, nil)
+		tool.provider = newTestPluginProvider()
+		tool.Checksums = map[string]string{"linux/amd64": "sha256:" + sha256Hex("tool v1.0.0 linux/amd64")}
+
+		binPath, err := b.Get(t.Context(), "tool")
+		assert.NilError(t, err)
+
+		blob, err := os.ReadFile(binPath)
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), "tool v1.0.0 linux/amd64")
+
+		marker, err := b.readVersionMarker(tool)
+		assert.NilError(t, err)
+		assert.Equal(t, marker.Checksum.Value, sha256Hex("tool v1.0.0 linux/amd64"))
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.Assert(t, items[0].OK, items[0].Problems)
+	})
+
+	t.Run("Rejects binaries that don't match the pinned checksum", func(t *testing.T) {
+		modifyRuntime(t, "linux", "amd64")
+		t.Setenv("BINE_HELPER_URL", "")
+
+		b, tool := newLockTestBine(t, nil)
+		tool.provider = newTestPluginProvider()
+		tool.Checksums = map[string]string{"linux/amd64": "sha256:" + sha256Hex("something else")}
+
+		_, err := b.Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, "archive checksum mismatch")
+		_, err = os.Stat(filepath.Join(b.BinDir, "tool"))
+		assert.Assert(t, os.IsNotExist(err))
+	})
+}
+
+func TestLoadProviderPlugin(t *testing.T) {
+	b := &bin{Name: "tool", Provider: "s3"}
+	assert.NilError(t, b.loadProvider(providerOptions{}))
+	provider, ok := b.provider.(*pluginProvider)
+	assert.Assert(t, ok)
+	assert.Equal(t, provider.kind, "s3")
+
+	b = &bin{Name: "tool", Provider: "../s3"}
+	assert.Error(t, b.loadProvider(providerOptions{}), `invalid provider name "../s3"`)
+}
@@ -0,0 +1,167 @@
+package bine
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+const (
+	schemeSemver  = "semver"
+	schemeCalver  = "calver"
+	schemeNumeric = "numeric"
+	schemeLexical = "lexical"
+)
+
+// versionScheme defines how the versions of a bin are validated and ordered
+// when looking for the latest version.
+type versionScheme struct {
+	name string
+	// pattern extracts the components compared by custom schemes, in the
+	// order of its capture groups.
+	pattern *regexp.Regexp
+}
+
+var (
+	defaultVersionScheme = &versionScheme{name: schemeSemver}
+
+	// calverVersion matches calendar versions, e.g. "2024.10.1", "24.04" or
+	// "2024-10-17".
+	calverVersion = regexp.MustCompile(`^\d+([.\-_]\d+)*$`)
+
+	// numericVersion matches build numbers with an optional prefix, e.g.
+	// "20241017" or "r27".
+	numericVersion = regexp.MustCompile(`^\D*(\d+)$`)
+)
+
+// parseVersionScheme parses the version_scheme of a bin: one of the known
+// schemes, or a regular expression whose capture groups are compared in
+// order, numerically when both are numbers.
+func parseVersionScheme(s string) (*versionScheme, error) {
+	switch s {
+	case "", schemeSemver:
+		return defaultVersionScheme, nil
+	case schemeCalver, schemeNumeric, schemeLexical:
+		return &versionScheme{name: s}, nil
+	}
+
+	pattern, err := regexp.Compile(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version_scheme %q: %v", s, err)
+	}
+	if pattern.NumSubexp() == 0 {
+		return nil, fmt.Errorf("invalid version_scheme %q: no capture groups", s)
+	}
+
+	return &versionScheme{name: s, pattern: pattern}, nil
+}
+
+func (s *versionScheme) semver() bool {
+	return s.name == schemeSemver
+}
+
+// components returns the parts of the version compared by the scheme, or
+// false if the version is not valid in this scheme.
+func (s *versionScheme) components(version string) ([]string, bool) {
+	version = strings.TrimPrefix(version, "v")
+	switch {
+	case s.pattern != nil:
+		m := s.pattern.FindStringSubmatch(version)
+		if m == nil {
+			return nil, false
+		}
+		return m[1:], true
+	case s.name == schemeCalver:
+		if !calverVersion.MatchString(version) {
+			return nil, false
+		}
+		return strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '-' || r == '_' }), true
+	case s.name == schemeNumeric:
+		m := numericVersion.FindStringSubmatch(version)
+		if m == nil {
+			return nil, false
+		}
+		return m[1:], true
+	case s.name == schemeLexical:
+		return []string{version}, version != ""
+	default:
+		canonical := semver.Canonical("v" + version)
+		return []string{canonical}, canonical != ""
+	}
+}
+
+// valid reports whether the version, with or without "v" prefix, is valid
+// in this scheme.
+func (s *versionScheme) valid(version string) bool {
+	_, ok := s.components(version)
+	return ok
+}
+
+// compare returns an integer comparing two versions. Invalid versions are
+// considered less than valid ones, and equal to each other.
+func (s *versionScheme) compare(a, b string) int {
+	if s.semver() {
+		return semver.Compare("v"+strings.TrimPrefix(a, "v"), "v"+strings.TrimPrefix(b, "v"))
+	}
+
+	ac, aok := s.components(a)
+	bc, bok := s.components(b)
+	switch {
+	case !aok && !bok:
+		return 0
+	case !aok:
+		return -1
+	case !bok:
+		return 1
+	}
+
+	for i := range max(len(ac), len(bc)) {
+		var x, y string
+		if i < len(ac) {
+			x = ac[i]
+		}
+		if i < len(bc) {
+			y = bc[i]
+		}
+		if c := compareComponent(x, y); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// compareComponent compares numbers by value, e.g. "10" comes after "9", and
+// anything else lexically. Missing components come first.
+func compareComponent(x, y string) int {
+	if isDigits(x) && isDigits(y) {
+		x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+		if len(x) != len(y) {
+			return cmp.Compare(len(x), len(y))
+		}
+	}
+	return strings.Compare(x, y)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// kind describes the scheme in error messages.
+func (s *versionScheme) kind() string {
+	if s.pattern != nil {
+		return "version_scheme"
+	}
+	return s.name
+}
@@ -0,0 +1,118 @@
+package bine
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestVersionScheme(t *testing.T) {
+	tests := []struct {
+		scheme string
+		// ordered from lowest to highest.
+		ordered []string
+		invalid []string
+	}{
+		{
+			scheme:  "",
+			ordered: []string{"1.9.0", "2.0.0-rc.1", "v2.0.0", "2.10.0"},
+			invalid: []string{"2024.10.01", "r27"},
+		},
+		{
+			scheme:  "calver",
+			ordered: []string{"24.04", "2024.9.30", "2024.10", "2024.10.1", "2024.10.17"},
+			invalid: []string{"2024.10.1-rc.1", "nightly"},
+		},
+		{
+			scheme:  "calver",
+			ordered: []string{"2024-09-30", "2024-10-01", "2024-10-17"},
+		},
+		{
+			scheme:  "numeric",
+			ordered: []string{"r9", "r27", "r100"},
+			invalid: []string{"1.2", "nightly"},
+		},
+		{
+			scheme:  "numeric",
+			ordered: []string{"9999999", "20241017", "20241018"},
+		},
+		{
+			scheme:  "lexical",
+			ordered: []string{"alpha", "beta", "gamma"},
+			invalid: []string{""},
+		},
+		{
+			scheme:  `^release-(\d+)\.(\d+)(?:-hotfix(\d+))?$`,
+			ordered: []string{"release-1.9", "release-1.9-hotfix2", "release-1.10"},
+			invalid: []string{"1.10", "release-1"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.scheme, func(t *testing.T) {
+			s, err := parseVersionScheme(tc.scheme)
+			assert.NilError(t, err)
+
+			for i, version := range tc.ordered {
+				assert.Assert(t, s.valid(version), version)
+				assert.Equal(t, s.compare(version, version), 0)
+				if i > 0 {
+					assert.Assert(t, s.compare(tc.ordered[i-1], version) < 0, "%s < %s", tc.ordered[i-1], version)
+					assert.Assert(t, s.compare(version, tc.ordered[i-1]) > 0, "%s > %s", version, tc.ordered[i-1])
+				}
+			}
+			for _, version := range tc.invalid {
+				assert.Assert(t, !s.valid(version), version)
+				assert.Assert(t, s.compare(version, tc.ordered[0]) < 0, version)
+			}
+
+			b := &bin{Name: "tool", VersionScheme: tc.scheme, scheme: s}
+			latest := tc.ordered[len(tc.ordered)-1]
+			assert.Equal(t, latestVersionOf(b, append(tc.invalid, tc.ordered...)), latest)
+		})
+	}
+
+	_, err := parseVersionScheme("(")
+	assert.ErrorContains(t, err, `invalid version_scheme "(": error parsing regexp`)
+	_, err = parseVersionScheme(`\d+`)
+	assert.Error(t, err, `invalid version_scheme "\\d+": no capture groups`)
+}
+
+func TestVersionSchemeBin(t *testing.T) {
+	t.Run("Checks non-semver versions for updates", func(t *testing.T) {
+		tool := &bin{
+			Name:          "tool",
+			URL:           "https://github.com/example/tool",
+			Version:       "2024.9.30",
+			VersionScheme: schemeCalver,
+		}
+		assert.NilError(t, tool.loadProvider(providerOptions{}))
+		tool.provider = staticProvider{latest: "2024.10.1"}
+		assert.Equal(t, tool.usableVersion(), "2024.9.30")
+
+		outdated, latest, err := tool.checkOutdated(t.Context(), "")
+		assert.NilError(t, err)
+		assert.Assert(t, outdated)
+		assert.Equal(t, latest, "2024.10.1")
+
+		tool.provider = staticProvider{latest: "nightly"}
+		_, _, err = tool.checkOutdated(t.Context(), "")
+		assert.Error(t, err, `invalid calver for latest version "nightly" of tool`)
+	})
+
+	t.Run("Extracts versions from tags", func(t *testing.T) {
+		tool := &bin{Name: "tool", TagPattern: "{version}", scheme: &versionScheme{name: schemeNumeric}}
+		assert.Equal(t, latestTaggedVersion(tool, []string{"r9", "r27", "r100", "v1.2.3"}), "r100")
+	})
+
+	t.Run("Doesn't parse versions as ranges", func(t *testing.T) {
+		tool := &bin{Name: "tool", URL: "https://github.com/example/tool", Version: "2024.10.*", VersionScheme: schemeLexical}
+		assert.NilError(t, tool.loadProvider(providerOptions{}))
+		assert.Assert(t, !tool.isRange())
+	})
+
+	t.Run("Rejects Go packages", func(t *testing.T) {
+		tool := &bin{Name: "tool", GoPackage: "example.com/tool", VersionScheme: schemeCalver}
+		err := tool.loadProvider(providerOptions{})
+		assert.Error(t, err, `version_scheme "calver" cannot be used with Go packages`)
+	})
+}
@@ -0,0 +1,324 @@
+package bine
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	signatureFormatMinisign = "minisign"
+	signatureFormatEd25519  = "ed25519"
+	signatureFormatOpenPGP  = "openpgp"
+)
+
+const (
+	signatureTargetAsset     = "asset"
+	signatureTargetChecksums = "checksums"
+)
+
+// binSignature configures the verification of detached signatures published
+// alongside the release asset.
+type binSignature struct {
+	// Format of the signature, "minisign" (default), "ed25519" or "openpgp".
+	Format string `json:"format,omitempty" toml:"format,omitempty"`
+
+	// Name of the signature asset. Supports the same variables as
+	// AssetPattern.
+	Pattern string `json:"pattern" toml:"pattern"`
+
+	// Signed file, "asset" (default) or "checksums" when the signature covers
+	// the checksum file instead, e.g. HashiCorp's SHA256SUMS.sig.
+	Target string `json:"target,omitempty" toml:"target,omitempty"`
+
+	// Trusted public key, base64-encoded. For minisign, the contents of the
+	// .pub file are accepted too.
+	PublicKey string `json:"public_key,omitempty" toml:"public_key,omitempty"`
+
+	// Path to an armored OpenPGP keyring, relative to the configuration file.
+	Keyring string `json:"keyring,omitempty" toml:"keyring,omitempty"`
+
+	// keyringPath is the absolute path of the keyring, resolved when the
+	// config is loaded.
+	keyringPath string
+}
+
+// signatureVerifier verifies detached signatures of downloaded assets.
+type signatureVerifier interface {
+	// keyID identifies the trusted key in error messages.
+	keyID() string
+	// verify checks the signature of the signed data and returns the
+	// identity of the signer.
+	verify(signed io.Reader, sig []byte) (string, error)
+}
+
+func (s *binSignature) verifier() (signatureVerifier, error) {
+	switch s.Format {
+	case "", signatureFormatMinisign:
+		return parseMinisignPublicKey(s.PublicKey)
+	case signatureFormatEd25519:
+		return parseEd25519PublicKey(s.PublicKey)
+	case signatureFormatOpenPGP:
+		return loadOpenPGPKeyring(s.keyringPath)
+	default:
+		return nil, fmt.Errorf("unsupported signature format %q", s.Format)
+	}
+}
+
+// verifySignature downloads the signature published with the asset and
+// verifies it against the trusted key. The signed data is either the archive
+// at archivePath or the contents of the checksum file. It returns the
+// identity of the signer.
+func verifySignature(ctx context.Context, client *http.Client, b *bin, archivePath string, checksums []byte) (string, error) {
+	v, err := b.Signature.verifier()
+	if err != nil {
+		return "", fmt.Errorf("signature: %v", err)
+	}
+
+	var (
+		signed      io.Reader
+		signedAsset string
+	)
+	switch b.Signature.Target {
+	case "", signatureTargetAsset:
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		signed, signedAsset = f, b.asset
+	case signatureTargetChecksums:
+		if checksums == nil {
+			return "", errors.New("signature: target \"checksums\" requires checksum_pattern")
+		}
+		signed, signedAsset = bytes.NewReader(checksums), b.checksumAsset
+	default:
+		return "", fmt.Errorf("signature: unsupported target %q", b.Signature.Target)
+	}
+
+	url, err := sidecarURL(ctx, b, b.signatureAsset)
+	if err != nil {
+		return "", fmt.Errorf("generate signature URL: %v", err)
+	}
+
+	sig, err := fetchSidecar(ctx, client, b, url)
+	if err != nil {
+		return "", fmt.Errorf("signature: %v", err)
+	}
+
+	signer, err := v.verify(signed, sig)
+	if err != nil {
+		return "", fmt.Errorf("signature verification failed (asset %q, key %s): %v", signedAsset, v.keyID(), err)
+	}
+
+	return signer, nil
+}
+
+// minisignVerifier verifies signatures made with minisign or signify-style
+// Ed25519 keys, see https://jedisct1.github.io/minisign/.
+type minisignVerifier struct {
+	id  [8]byte
+	key ed25519.PublicKey
+}
+
+var _ signatureVerifier = &minisignVerifier{}
+
+func parseMinisignPublicKey(s string) (*minisignVerifier, error) {
+	blob, err := base64.StdEncoding.DecodeString(lastLine(s))
+	if err != nil || len(blob) != 42 || string(blob[:2]) != "Ed" {
+		return nil, errors.New("invalid minisign public key")
+	}
+
+	v := &minisignVerifier{key: ed25519.PublicKey(blob[10:])}
+	copy(v.id[:], blob[2:10])
+
+	return v, nil
+}
+
+// keyID formats the key ID the same way minisign does.
+func (v *minisignVerifier) keyID() string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(v.id[:]))
+}
+
+func (v *minisignVerifier) verify(signed io.Reader, sig []byte) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(string(sig), "\r\n", "\n"), "\n")
+	if len(lines) < 4 {
+		return "", errors.New("malformed minisign signature")
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(blob) != 74 {
+		return "", errors.New("malformed minisign signature")
+	}
+	if !bytes.Equal(blob[2:10], v.id[:]) {
+		return "", fmt.Errorf("signature was made with key %016X", binary.LittleEndian.Uint64(blob[2:10]))
+	}
+	signature := blob[10:]
+
+	var msg []byte
+	switch string(blob[:2]) {
+	case "Ed":
+		if msg, err = io.ReadAll(signed); err != nil {
+			return "", err
+		}
+	case "ED":
+		h, _ := blake2b.New512(nil)
+		if _, err := io.Copy(h, signed); err != nil {
+			return "", err
+		}
+		msg = h.Sum(nil)
+	default:
+		return "", fmt.Errorf("unsupported minisign signature algorithm %q", blob[:2])
+	}
+	if !ed25519.Verify(v.key, msg, signature) {
+		return "", errors.New("invalid signature")
+	}
+
+	// The global signature covers the trusted comment.
+	trustedComment, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return "", errors.New("malformed minisign signature: missing trusted comment")
+	}
+	globalSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSignature) != ed25519.SignatureSize {
+		return "", errors.New("malformed minisign signature: invalid global signature")
+	}
+	if !ed25519.Verify(v.key, append(bytes.Clone(signature), trustedComment...), globalSignature) {
+		return "", errors.New("invalid global signature")
+	}
+
+	return v.keyID(), nil
+}
+
+// ed25519Verifier verifies raw Ed25519 signatures of the whole asset.
+type ed25519Verifier struct {
+	key ed25519.PublicKey
+}
+
+var _ signatureVerifier = &ed25519Verifier{}
+
+func parseEd25519PublicKey(s string) (*ed25519Verifier, error) {
+	blob, ok := decodeKeyMaterial(s, ed25519.PublicKeySize)
+	if !ok {
+		return nil, errors.New("invalid ed25519 public key")
+	}
+
+	return &ed25519Verifier{key: ed25519.PublicKey(blob)}, nil
+}
+
+// keyID returns the first eight bytes of the SHA-256 digest of the key.
+func (v *ed25519Verifier) keyID() string {
+	sum := sha256.Sum256(v.key)
+	return strings.ToUpper(hex.EncodeToString(sum[:8]))
+}
+
+func (v *ed25519Verifier) verify(signed io.Reader, sig []byte) (string, error) {
+	signature, ok := decodeKeyMaterial(string(sig), ed25519.SignatureSize)
+	if !ok {
+		return "", errors.New("malformed ed25519 signature")
+	}
+
+	msg, err := io.ReadAll(signed)
+	if err != nil {
+		return "", err
+	}
+	if !ed25519.Verify(v.key, msg, signature) {
+		return "", errors.New("invalid signature")
+	}
+
+	return v.keyID(), nil
+}
+
+// openPGPVerifier verifies OpenPGP detached signatures against a keyring
+// committed to the project. Verification runs offline.
+type openPGPVerifier struct {
+	keyring openpgp.EntityList
+}
+
+var _ signatureVerifier = &openPGPVerifier{}
+
+func loadOpenPGPKeyring(path string) (*openPGPVerifier, error) {
+	if path == "" {
+		return nil, errors.New("openpgp signatures require a keyring")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open keyring: %v", err)
+	}
+	defer f.Close()
+
+	keyring, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("read keyring %q: %v", path, err)
+	}
+
+	return &openPGPVerifier{keyring: keyring}, nil
+}
+
+// keyID lists the fingerprints of the primary keys in the keyring.
+func (v *openPGPVerifier) keyID() string {
+	fingerprints := make([]string, 0, len(v.keyring))
+	for _, entity := range v.keyring {
+		fingerprints = append(fingerprints, openPGPFingerprint(entity))
+	}
+	return strings.Join(fingerprints, ",")
+}
+
+// verify accepts armored (.asc) and binary (.sig) signatures and returns the
+// fingerprint of the signing key.
+func (v *openPGPVerifier) verify(signed io.Reader, sig []byte) (string, error) {
+	var (
+		signer *openpgp.Entity
+		err    error
+	)
+	if bytes.HasPrefix(bytes.TrimSpace(sig), []byte("-----BEGIN")) {
+		signer, err = openpgp.CheckArmoredDetachedSignature(v.keyring, signed, bytes.NewReader(sig), nil)
+	} else {
+		signer, err = openpgp.CheckDetachedSignature(v.keyring, signed, bytes.NewReader(sig), nil)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return openPGPFingerprint(signer), nil
+}
+
+func openPGPFingerprint(entity *openpgp.Entity) string {
+	return strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))
+}
+
+// decodeKeyMaterial accepts raw, base64 or hex-encoded bytes of the given
+// size.
+func decodeKeyMaterial(s string, size int) ([]byte, bool) {
+	if len(s) == size {
+		return []byte(s), true
+	}
+	s = strings.TrimSpace(s)
+	if blob, err := base64.StdEncoding.DecodeString(s); err == nil && len(blob) == size {
+		return blob, true
+	}
+	if blob, err := hex.DecodeString(s); err == nil && len(blob) == size {
+		return blob, true
+	}
+	return nil, false
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,260 @@
+package bine
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"golang.org/x/crypto/blake2b"
+	"gotest.tools/v3/assert"
+)
+
+var minisignTestKeyID = []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+func newMinisignTestKey(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NilError(t, err)
+
+	blob := append([]byte("Ed"), minisignTestKeyID...)
+	blob = append(blob, pub...)
+
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(blob) + "\n", priv
+}
+
+func minisignSign(priv ed25519.PrivateKey, data []byte, prehashed bool) string {
+	alg, msg := "Ed", data
+	if prehashed {
+		sum := blake2b.Sum512(data)
+		alg, msg = "ED", sum[:]
+	}
+
+	signature := ed25519.Sign(priv, msg)
+	blob := append([]byte(alg), minisignTestKeyID...)
+	blob = append(blob, signature...)
+
+	trustedComment := "timestamp:1700000000"
+	globalSignature := ed25519.Sign(priv, append(signature, trustedComment...))
+
+	return fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(blob),
+		trustedComment,
+		base64.StdEncoding.EncodeToString(globalSignature),
+	)
+}
+
+func TestMinisignVerifier(t *testing.T) {
+	pub, priv := newMinisignTestKey(t)
+	v, err := parseMinisignPublicKey(pub)
+	assert.NilError(t, err)
+	assert.Equal(t, v.keyID(), "0807060504030201")
+
+	t.Run("Verifies prehashed signatures", func(t *testing.T) {
+		signer, err := v.verify(strings.NewReader("binary"), []byte(minisignSign(priv, []byte("binary"), true)))
+		assert.NilError(t, err)
+		assert.Equal(t, signer, "0807060504030201")
+	})
+
+	t.Run("Verifies legacy signatures", func(t *testing.T) {
+		signer, err := v.verify(strings.NewReader("binary"), []byte(minisignSign(priv, []byte("binary"), false)))
+		assert.NilError(t, err)
+		assert.Equal(t, signer, "0807060504030201")
+	})
+
+	t.Run("Rejects signatures of other data", func(t *testing.T) {
+		_, err := v.verify(strings.NewReader("binary"), []byte(minisignSign(priv, []byte("other"), true)))
+		assert.Error(t, err, "invalid signature")
+	})
+
+	t.Run("Rejects signatures made with other keys", func(t *testing.T) {
+		_, other := newMinisignTestKey(t)
+		_, err := v.verify(strings.NewReader("binary"), []byte(minisignSign(other, []byte("binary"), true)))
+		assert.Error(t, err, "invalid signature")
+	})
+
+	t.Run("Rejects malformed signatures", func(t *testing.T) {
+		_, err := v.verify(strings.NewReader("binary"), []byte("garbage"))
+		assert.Error(t, err, "malformed minisign signature")
+	})
+
+	t.Run("Rejects invalid public keys", func(t *testing.T) {
+		_, err := parseMinisignPublicKey("RWQ")
+		assert.Error(t, err, "invalid minisign public key")
+	})
+}
+
+func TestEd25519Verifier(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NilError(t, err)
+
+	v, err := parseEd25519PublicKey(base64.StdEncoding.EncodeToString(pub))
+	assert.NilError(t, err)
+	assert.Equal(t, len(v.keyID()), 16)
+
+	t.Run("Verifies raw signatures", func(t *testing.T) {
+		signer, err := v.verify(strings.NewReader("binary"), ed25519.Sign(priv, []byte("binary")))
+		assert.NilError(t, err)
+		assert.Equal(t, signer, v.keyID())
+	})
+
+	t.Run("Verifies encoded signatures", func(t *testing.T) {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("binary")))
+		_, err := v.verify(strings.NewReader("binary"), []byte(sig+"\n"))
+		assert.NilError(t, err)
+	})
+
+	t.Run("Rejects signatures of other data", func(t *testing.T) {
+		_, err := v.verify(strings.NewReader("binary"), ed25519.Sign(priv, []byte("other")))
+		assert.Error(t, err, "invalid signature")
+	})
+}
+
+func TestBinInstallVerifiesSignature(t *testing.T) {
+	pub, priv := newMinisignTestKey(t)
+
+	t.Run("Installs when the signature is valid", func(t *testing.T) {
+		b, tool := newLockTestBine(t, map[string]string{
+			"/tool_1.0.0":         "binary",
+			"/tool_1.0.0.minisig": minisignSign(priv, []byte("binary"), true),
+		})
+		tool.Signature = &binSignature{PublicKey: pub}
+		tool.signatureAsset = "tool_1.0.0.minisig"
+
+		_, err := b.Get(t.Context(), tool.Name)
+		assert.NilError(t, err)
+	})
+
+	t.Run("Fails when the signature is invalid", func(t *testing.T) {
+		b, tool := newLockTestBine(t, map[string]string{
+			"/tool_1.0.0":         "binary",
+			"/tool_1.0.0.minisig": minisignSign(priv, []byte("other"), true),
+		})
+		tool.Signature = &binSignature{PublicKey: pub}
+		tool.signatureAsset = "tool_1.0.0.minisig"
+
+		_, err := b.Get(t.Context(), tool.Name)
+		assert.ErrorContains(t, err, `"tool": failed to install binary: signature verification failed (asset "tool_1.0.0", key 0807060504030201): invalid signature`)
+
+		_, err = os.Stat(filepath.Join(b.BinDir, tool.Name))
+		assert.Assert(t, os.IsNotExist(err))
+	})
+}
+
+func newOpenPGPTestKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("bine", "test", "bine@example.com", &packet.Config{
+		Algorithm: packet.PubKeyAlgoEdDSA,
+	})
+	assert.NilError(t, err)
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, entity.Serialize(w))
+	assert.NilError(t, w.Close())
+
+	return entity, buf.String()
+}
+
+func openPGPSign(t *testing.T, entity *openpgp.Entity, data string, armored bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if armored {
+		assert.NilError(t, openpgp.ArmoredDetachSign(&buf, entity, strings.NewReader(data), nil))
+	} else {
+		assert.NilError(t, openpgp.DetachSign(&buf, entity, strings.NewReader(data), nil))
+	}
+
+	return buf.Bytes()
+}
+
+func TestOpenPGPVerifier(t *testing.T) {
+	entity, pub := newOpenPGPTestKey(t)
+	keyring := filepath.Join(t.TempDir(), "keyring.asc")
+	assert.NilError(t, os.WriteFile(keyring, []byte(pub), 0o644))
+
+	v, err := loadOpenPGPKeyring(keyring)
+	assert.NilError(t, err)
+	assert.Equal(t, v.keyID(), openPGPFingerprint(entity))
+
+	t.Run("Verifies armored signatures", func(t *testing.T) {
+		signer, err := v.verify(strings.NewReader("binary"), openPGPSign(t, entity, "binary", true))
+		assert.NilError(t, err)
+		assert.Equal(t, signer, openPGPFingerprint(entity))
+	})
+
+	t.Run("Verifies binary signatures", func(t *testing.T) {
+		signer, err := v.verify(strings.NewReader("binary"), openPGPSign(t, entity, "binary", false))
+		assert.NilError(t, err)
+		assert.Equal(t, signer, openPGPFingerprint(entity))
+	})
+
+	t.Run("Rejects signatures of other data", func(t *testing.T) {
+		_, err := v.verify(strings.NewReader("binary"), openPGPSign(t, entity, "other", true))
+		assert.ErrorContains(t, err, "signature")
+	})
+
+	t.Run("Rejects signatures made with unknown keys", func(t *testing.T) {
+		other, _ := newOpenPGPTestKey(t)
+		_, err := v.verify(strings.NewReader("binary"), openPGPSign(t, other, "binary", true))
+		assert.ErrorContains(t, err, "signature made by unknown entity")
+	})
+
+	t.Run("Requires a keyring", func(t *testing.T) {
+		_, err := loadOpenPGPKeyring("")
+		assert.Error(t, err, "openpgp signatures require a keyring")
+	})
+}
+
+func TestBinInstallVerifiesSignedChecksumFile(t *testing.T) {
+	entity, pub := newOpenPGPTestKey(t)
+	keyring := filepath.Join(t.TempDir(), "keyring.asc")
+	assert.NilError(t, os.WriteFile(keyring, []byte(pub), 0o644))
+
+	checksums := fmt.Sprintf("%s  tool_1.0.0\n", sha256Hex("binary"))
+	b, tool := newLockTestBine(t, map[string]string{
+		"/tool_1.0.0":         "binary",
+		"/SHA256SUMS":         checksums,
+		"/SHA256SUMS.sig":     string(openPGPSign(t, entity, checksums, false)),
+		"/SHA256SUMS.bad.sig": string(openPGPSign(t, entity, "other", false)),
+	})
+	tool.checksumAsset = "SHA256SUMS"
+	tool.Signature = &binSignature{
+		Format:      signatureFormatOpenPGP,
+		Target:      signatureTargetChecksums,
+		keyringPath: keyring,
+	}
+
+	t.Run("Records the signer", func(t *testing.T) {
+		tool.signatureAsset = "SHA256SUMS.sig"
+
+		_, err := b.GetForce(t.Context(), tool.Name)
+		assert.NilError(t, err)
+
+		marker, err := b.readVersionMarker(tool)
+		assert.NilError(t, err)
+		assert.Equal(t, marker.Signer, openPGPFingerprint(entity))
+
+		items, err := b.List(t.Context(), true, false)
+		assert.NilError(t, err)
+		assert.Equal(t, items[0].Signer, openPGPFingerprint(entity))
+	})
+
+	t.Run("Fails when the checksum file signature is invalid", func(t *testing.T) {
+		tool.signatureAsset = "SHA256SUMS.bad.sig"
+
+		_, err := b.GetForce(t.Context(), tool.Name)
+		assert.ErrorContains(t, err, fmt.Sprintf(`signature verification failed (asset "SHA256SUMS", key %s)`, openPGPFingerprint(entity)))
+	})
+}
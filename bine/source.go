@@ -0,0 +1,150 @@
+package bine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// sourceProvider builds binaries from the sources found in a Git repository,
+// for tools that don't publish binaries for every platform. The tag is
+// checked out in a temporary workspace where the build recipe runs.
+type sourceProvider struct{}
+
+var (
+	_ binProvider = &sourceProvider{}
+	_ installer   = &sourceProvider{}
+)
+
+func newSourceProvider(b *bin) (*sourceProvider, error) {
+	if b.URL == "" {
+		return nil, fmt.Errorf("url is required by the %q provider", providerSource)
+	}
+	if b.Build == "" {
+		return nil, fmt.Errorf("build is required by the %q provider", providerSource)
+	}
+
+	return &sourceProvider{}, nil
+}
+
+// downloadURL returns no URL so the binary is built by install.
+func (p *sourceProvider) downloadURL(context.Context, *bin) (string, error) {
+	return "", nil
+}
+
+func (p *sourceProvider) latestVersion(ctx context.Context, bin *bin) (string, error) {
+	out, err := git(ctx, "", "ls-remote", "--tags", "--refs", bin.URL)
+	if err != nil {
+		return "", err
+	}
+
+	var tags []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		_, ref, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		tag, ok := strings.CutPrefix(ref, "refs/tags/")
+		if !ok {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	latestVersion := latestTaggedVersion(bin, tags)
+	if latestVersion == "" {
+		return "", fmt.Errorf("no valid %s tags found in Git repository matching tag pattern", bin.versionKind())
+	}
+
+	return latestVersion, nil
+}
+
+// install clones the tag of the bin, runs the build recipe and copies the
+// output to binPath. It returns the commit that was built.
+func (p *sourceProvider) install(ctx context.Context, b *bin, binPath string) (string, error) {
+	workspace, err := os.MkdirTemp("", "bine-source-*")
+	if err != nil {
+		return "", fmt.Errorf("create workspace: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(workspace) }()
+
+	if _, err := git(ctx, "", "clone", "--quiet", "--depth", "1", "--branch", b.tag(), b.URL, workspace); err != nil {
+		return "", err
+	}
+	out, err := git(ctx, workspace, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	commit := strings.TrimSpace(string(out))
+
+	cmd := execCommand(ctx, "sh", "-c", b.Build)
+	cmd.Dir = workspace
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("build %q failed: %v\noutput: %s", b.Build, err, strings.TrimSpace(output.String()))
+	}
+
+	outputPath := filepath.Join(workspace, filepath.FromSlash(b.buildOutput()))
+	if rel, err := filepath.Rel(workspace, outputPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("build output %q is outside of the workspace", b.buildOutput())
+	}
+	if err := copyFile(outputPath, binPath); err != nil {
+		return "", fmt.Errorf("copy build output: %v", err)
+	}
+
+	return commit, nil
+}
+
+// git runs a git command in dir and returns its standard output.
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	gitBin, err := exec.LookPath("git")
+	if err != nil {
+		return nil, fmt.Errorf("cannot find 'git' command: %v", err)
+	}
+
+	cmd := execCommand(ctx, gitBin, args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = "(no stderr output)"
+		}
+		return nil, fmt.Errorf("`git %s` failed: %v\nstderr: %s", args[0], err, msg)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// copyFile copies the contents of src to dst, which may be on another file
+// system.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
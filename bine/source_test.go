@@ -0,0 +1,140 @@
+package bine
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// newSourceTestRepo creates a bare Git repository with a few tagged releases
+// and returns its path and the commits keyed by tag.
+func newSourceTestRepo(t *testing.T) (string, map[string]string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work")
+	bare := filepath.Join(dir, "tool.git")
+
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=bine", "-c", "user.email=bine@example.com", "-c", "init.defaultBranch=main"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		assert.NilError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	assert.NilError(t, os.MkdirAll(work, 0o750))
+	run(work, "init", "--quiet")
+	commits := map[string]string{}
+	for _, tag := range []string{"v1.0.0", "v1.1.0", "v2.0.0-rc.1"} {
+		assert.NilError(t, os.WriteFile(filepath.Join(work, "VERSION"), []byte(tag), 0o600))
+		run(work, "add", "VERSION")
+		run(work, "commit", "--quiet", "-m", tag)
+		run(work, "tag", tag)
+		commits[tag] = run(work, "rev-parse", "HEAD")
+	}
+	run(dir, "clone", "--quiet", "--bare", work, bare)
+
+	return bare, commits
+}
+
+func TestSourceProvider(t *testing.T) {
+	repo, commits := newSourceTestRepo(t)
+
+	newSourceTestBine := func(t *testing.T) (*Bine, *bin) {
+		t.Helper()
+
+		b, tool := newLockTestBine(t, nil)
+		tool.URL = repo
+		tool.Provider = providerSource
+		tool.Build = "mkdir -p out && cat VERSION > out/tool"
+		tool.BuildOutput = "out/{name}"
+		tool.provider = nil
+		assert.NilError(t, tool.loadProvider(providerOptions{}))
+
+		return b, tool
+	}
+
+	t.Run("latestVersion skips prereleases", func(t *testing.T) {
+		_, tool := newSourceTestBine(t)
+
+		latest, err := tool.provider.latestVersion(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "1.1.0")
+	})
+
+	t.Run("Builds the tag and records the commit", func(t *testing.T) {
+		b, tool := newSourceTestBine(t)
+
+		binPath, err := b.Get(t.Context(), "tool")
+		assert.NilError(t, err)
+
+		blob, err := os.ReadFile(binPath)
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), "v1.0.0")
+
+		marker, err := b.readVersionMarker(tool)
+		assert.NilError(t, err)
+		assert.Equal(t, marker.Commit, commits["v1.0.0"])
+		assert.Assert(t, marker.ArchiveChecksum == nil)
+	})
+
+	t.Run("Locks the commit", func(t *testing.T) {
+		b, tool := newSourceTestBine(t)
+
+		assert.NilError(t, b.Lock(t.Context()))
+		lock, err := loadLockFile(lockFilePath(b.config.path))
+		assert.NilError(t, err)
+		locked, err := lock.asset(tool)
+		assert.NilError(t, err)
+		assert.Equal(t, locked.Commit, commits["v1.0.0"])
+		assert.Equal(t, locked.BinarySHA256, "")
+		b.config.lock = lock
+
+		ok, err := b.installed(t.Context(), tool)
+		assert.NilError(t, err)
+		assert.Assert(t, ok)
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.Assert(t, items[0].OK, items[0].Problems)
+
+		locked.Commit = commits["v1.1.0"]
+		_, err = b.GetForce(t.Context(), "tool")
+		assert.ErrorContains(t, err, "commit mismatch")
+	})
+
+	t.Run("Fails when the build fails", func(t *testing.T) {
+		b, tool := newSourceTestBine(t)
+		tool.Build = "echo oops; exit 1"
+
+		_, err := b.Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, `build "echo oops; exit 1" failed: exit status 1`)
+		assert.ErrorContains(t, err, "oops")
+	})
+
+	t.Run("Rejects outputs outside of the workspace", func(t *testing.T) {
+		b, tool := newSourceTestBine(t)
+		tool.BuildOutput = "../tool"
+
+		_, err := b.Get(t.Context(), "tool")
+		assert.ErrorContains(t, err, `build output "../tool" is outside of the workspace`)
+	})
+}
+
+func TestLoadProviderSource(t *testing.T) {
+	b := &bin{Name: "tool", Provider: "source", URL: "https://example.com/tool.git"}
+	assert.Error(t, b.loadProvider(providerOptions{}), `build is required by the "source" provider`)
+
+	b = &bin{Name: "tool", Provider: "source", Build: "make"}
+	assert.Error(t, b.loadProvider(providerOptions{}), `url is required by the "source" provider`)
+}
@@ -0,0 +1,147 @@
+package bine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// VerifyItem reports the outcome of verifying an installed binary.
+type VerifyItem struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	OK      bool   `json:"ok"`
+	// Problems lists every check that failed.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Verify checks the integrity of every binary in the cache without modifying
+// it. Unlike Get or Sync, it reports binaries that don't match their version
+// marker, the lock file or the pinned checksums instead of reinstalling them.
+func (b *Bine) Verify(ctx context.Context) ([]*VerifyItem, error) {
+	return b.verifyBins(ctx, b.config.Bins)
+}
+
+// VerifyOne checks the integrity of a single binary defined in the
+// configuration.
+func (b *Bine) VerifyOne(ctx context.Context, name string) ([]*VerifyItem, error) {
+	selected, err := b.load(name)
+	if err != nil {
+		return nil, fmt.Errorf("verify: %v", err)
+	}
+
+	return b.verifyBins(ctx, []*bin{selected})
+}
+
+func (b *Bine) verifyBins(ctx context.Context, bins []*bin) ([]*VerifyItem, error) {
+	items := make([]*VerifyItem, 0, len(bins))
+	for _, bin := range bins {
+		item, err := b.verifyBin(ctx, bin)
+		if err != nil {
+			return nil, fmt.Errorf("verify %s: %v", bin.Name, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (b *Bine) verifyBin(ctx context.Context, bin *bin) (*VerifyItem, error) {
+	item := &VerifyItem{Name: bin.Name, Version: bin.usableVersion()}
+	defer func() { item.OK = len(item.Problems) == 0 }()
+
+	problem := func(format string, args ...any) {
+		item.Problems = append(item.Problems, fmt.Sprintf(format, args...))
+	}
+
+	binPath := filepath.Join(b.BinDir, bin.Name)
+	if info, err := os.Stat(binPath); os.IsNotExist(err) {
+		problem("binary is not installed")
+		return item, nil
+	} else if err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		problem("expected %q to be a file, but it's a directory", binPath)
+		return item, nil
+	}
+
+	marker, err := b.readVersionMarker(bin)
+	if os.IsNotExist(err) {
+		problem("version marker is missing")
+		return item, nil
+	} else if err != nil {
+		problem("version marker is unreadable: %v", err)
+		return item, nil
+	}
+	if (bin.isLatest() || bin.isRange()) && marker.ResolvedVersion != "" {
+		item.Version = bin.displayVersion(marker.ResolvedVersion)
+	}
+
+	sum, err := checksum(binPath)
+	if err != nil {
+		return nil, fmt.Errorf("checksum: %v", err)
+	}
+	if marker.Checksum.Value == "" {
+		problem("version marker has no checksum")
+	} else if !marker.Checksum.Matches(sum) {
+		problem("binary checksum %s does not match version marker (%s)", sum, marker.Checksum.Value)
+	}
+
+	if locked, err := b.config.lock.asset(bin); err != nil {
+		problem("%v", err)
+	} else if locked != nil && locked.BinarySHA256 != "" && locked.BinarySHA256 != marker.Checksum.Value {
+		problem("version marker checksum %s does not match lock file (%s)", marker.Checksum.Value, locked.BinarySHA256)
+	} else if locked != nil && locked.Commit != marker.Commit {
+		problem("version marker commit %q does not match lock file (%q)", marker.Commit, locked.Commit)
+	}
+
+	if pinned, err := bin.pinnedChecksum(); err != nil {
+		problem("%v", err)
+	} else if pinned != nil && marker.ArchiveChecksum == nil {
+		problem("version marker has no archive checksum")
+	} else if pinned != nil && *marker.ArchiveChecksum != *pinned {
+		problem("version marker archive checksum %s does not match pinned checksum (%s)", marker.ArchiveChecksum, pinned)
+	}
+
+	if bin.goPkg() {
+		b.verifyGoBuildInfo(ctx, bin, binPath, marker, problem)
+	}
+
+	return item, nil
+}
+
+// verifyGoBuildInfo checks that a binary installed with "go install" was built
+// from the configured package, version and module sum.
+func (b *Bine) verifyGoBuildInfo(ctx context.Context, bin *bin, binPath string, marker *versionMarkerDocument, problem func(string, ...any)) {
+	info, err := readGoBuildInfo(ctx, binPath)
+	if err != nil {
+		problem("cannot read build information: %v", err)
+		return
+	}
+
+	if info.Path != bin.GoPackage {
+		problem("binary was built from package %q, configured %q", info.Path, bin.GoPackage)
+	}
+
+	expected := bin.canonicalVersion()
+	if bin.isLatest() || bin.isRange() {
+		expected = ""
+		if marker.ResolvedVersion != "" {
+			expected = bin.displayVersion(marker.ResolvedVersion)
+		}
+	}
+	if expected != "" && info.Version != expected {
+		problem("binary was built from module version %s, expected %s", info.Version, expected)
+	}
+
+	if marker.ModuleSum != "" && info.Sum != marker.ModuleSum {
+		problem("binary was built from module sum %s, version marker has %s", info.Sum, marker.ModuleSum)
+	}
+	if lockedSum, err := b.config.lock.moduleSum(bin); err == nil && lockedSum != "" && lockedSum != info.Sum {
+		problem("binary was built from module sum %s, lock file has %s", info.Sum, lockedSum)
+	}
+	if settings := bin.goBuildSettings(); !marker.GoBuild.equal(settings) {
+		problem("binary was built with Go build settings %s, configured %s", marker.GoBuild, settings)
+	}
+}
@@ -0,0 +1,112 @@
+package bine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestVerify(t *testing.T) {
+	b, tool := newLockTestBine(t, map[string]string{"/tool_1.0.0": "binary-1"})
+
+	t.Run("Reports binaries that are not installed", func(t *testing.T) {
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, items, []*VerifyItem{
+			{Name: "tool", Version: "v1.0.0", Problems: []string{"binary is not installed"}},
+		})
+	})
+
+	path, err := b.Get(t.Context(), tool.Name)
+	assert.NilError(t, err)
+
+	t.Run("Accepts intact binaries", func(t *testing.T) {
+		items, err := b.VerifyOne(t.Context(), tool.Name)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, items, []*VerifyItem{{Name: "tool", Version: "v1.0.0", OK: true}})
+	})
+
+	t.Run("Reports binaries not matching the lock file", func(t *testing.T) {
+		b.config.lock = newLockFile(lockFilePath(b.config.path))
+		b.config.lock.set(tool, &lockedAsset{BinarySHA256: sha256Hex("binary-2")})
+		t.Cleanup(func() { b.config.lock = nil })
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.Assert(t, !items[0].OK)
+		assert.DeepEqual(t, items[0].Problems, []string{
+			"version marker checksum " + sha256Hex("binary-1") + " does not match lock file (" + sha256Hex("binary-2") + ")",
+		})
+	})
+
+	t.Run("Reports tampered binaries without reinstalling them", func(t *testing.T) {
+		assert.NilError(t, os.WriteFile(path, []byte("tampered"), 0o755))
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, items[0].Problems, []string{
+			"binary checksum " + sha256Hex("tampered") + " does not match version marker (" + sha256Hex("binary-1") + ")",
+		})
+
+		blob, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Equal(t, string(blob), "tampered")
+	})
+
+	t.Run("Reports missing version markers", func(t *testing.T) {
+		assert.NilError(t, os.RemoveAll(filepath.Join(b.VersionsDir, tool.Name)))
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, items[0].Problems, []string{"version marker is missing"})
+	})
+}
+
+func TestVerifyGoBuildInfo(t *testing.T) {
+	injectFakeExec(t, "TestHelperProcessGoVersionM")
+
+	b, tool := newLatestTrackingTestBine(t, "1.2.3")
+	writeLatestTrackingBinary(t, b, tool)
+
+	t.Run("Accepts the resolved version", func(t *testing.T) {
+		assert.NilError(t, b.markVersion(tool, "1.2.3"))
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, items, []*VerifyItem{{Name: "tool", Version: "v1.2.3", OK: true}})
+	})
+
+	t.Run("Reports a different module version", func(t *testing.T) {
+		assert.NilError(t, b.markVersion(tool, "1.3.0"))
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, items[0].Problems, []string{"binary was built from module version v1.2.3, expected v1.3.0"})
+	})
+
+	t.Run("Reports a different package", func(t *testing.T) {
+		assert.NilError(t, b.markVersion(tool, "1.2.3"))
+		tool.GoPackage = "github.com/foo/baz/cmd/tool"
+		t.Cleanup(func() { tool.GoPackage = "github.com/foo/bar/cmd/tool" })
+
+		items, err := b.Verify(t.Context())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, items[0].Problems, []string{`binary was built from package "github.com/foo/bar/cmd/tool", configured "github.com/foo/baz/cmd/tool"`})
+	})
+}
+
+func TestVerifyVersionScheme(t *testing.T) {
+	b, tool := newLockTestBine(t, nil)
+	tool.Version = "latest"
+	tool.VersionScheme = schemeCalver
+	tool.scheme = &versionScheme{name: schemeCalver}
+	assert.NilError(t, os.MkdirAll(b.BinDir, 0o750))
+	writeLatestTrackingBinary(t, b, tool)
+	assert.NilError(t, b.markVersion(tool, "2024.10.1"))
+
+	items, err := b.Verify(t.Context())
+	assert.NilError(t, err)
+	assert.Equal(t, items[0].Version, "2024.10.1")
+}
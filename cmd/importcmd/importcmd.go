@@ -0,0 +1,83 @@
+package importcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/peterbourgon/ff/v4"
+
+	"github.com/artefactual-labs/bine/cmd/rootcmd"
+)
+
+type Config struct {
+	*rootcmd.RootConfig
+	Command *ff.Command
+	Flags   *ff.FlagSet
+	Pin     bool
+}
+
+func New(parent *rootcmd.RootConfig) *Config {
+	var cfg Config
+	cfg.RootConfig = parent
+	cfg.Flags = ff.NewFlagSet("import").SetParent(parent.Flags)
+
+	cfg.Command = &ff.Command{
+		Name:      "import",
+		Usage:     "bine import <SUBCOMMAND>",
+		ShortHelp: "Add binaries to the configuration file from other sources.",
+		Flags:     cfg.Flags,
+		Exec:      cfg.Exec,
+	}
+
+	// Add go-mod subcommand.
+	goModFlags := ff.NewFlagSet("go-mod").SetParent(cfg.Flags)
+	goModFlags.BoolVar(&cfg.Pin, 0, "pin", "Pin the versions required by go.mod instead of following them.")
+	goModCmd := &ff.Command{
+		Name:      "go-mod",
+		Usage:     "bine import go-mod [PATH]",
+		ShortHelp: "Add the tool directives of go.mod as Go packages.",
+		LongHelp: `Adds a go_package bin for every tool directive of the go.mod file found next
+to the configuration file, or at PATH. The bins are added with go_mod = true so
+they always install the version required by go.mod. Use --pin to record the
+versions in the configuration file instead.
+
+Tools that are already configured and tools provided by the main module are
+skipped.`,
+		Flags: goModFlags,
+		Exec:  cfg.ExecGoMod,
+	}
+	cfg.Command.Subcommands = append(cfg.Command.Subcommands, goModCmd)
+
+	cfg.RootConfig.Command.Subcommands = append(cfg.RootConfig.Command.Subcommands, cfg.Command)
+	return &cfg
+}
+
+func (cfg *Config) Exec(ctx context.Context, args []string) error {
+	return errors.New("import command requires a subcommand (go-mod)")
+}
+
+func (cfg *Config) ExecGoMod(ctx context.Context, args []string) error {
+	if len(args) > 1 {
+		return errors.New("import go-mod accepts at most one argument")
+	}
+
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	names, err := cfg.Bine.ImportGoMod(path, cfg.Pin)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(cfg.Stdout, "No new tools found.")
+		return nil
+	}
+	for _, name := range names {
+		fmt.Fprintf(cfg.Stdout, "Added %s.\n", name)
+	}
+
+	return nil
+}
@@ -60,6 +60,9 @@ func (cfg *Config) Exec(ctx context.Context, _ []string) error {
 			if item.Latest != "" {
 				line += fmt.Sprintf(" » %s", item.Latest)
 			}
+			if item.LatestOverall != "" {
+				line += fmt.Sprintf(" (latest %s)", item.LatestOverall)
+			}
 			fmt.Fprintln(cfg.Stdout, line)
 		}
 	}
@@ -0,0 +1,48 @@
+package lockcmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/peterbourgon/ff/v4"
+
+	"github.com/artefactual-labs/bine/cmd/rootcmd"
+)
+
+type Config struct {
+	*rootcmd.RootConfig
+	Command *ff.Command
+	Flags   *ff.FlagSet
+}
+
+func New(parent *rootcmd.RootConfig) *Config {
+	var cfg Config
+	cfg.RootConfig = parent
+	cfg.Flags = ff.NewFlagSet("lock").SetParent(parent.Flags)
+
+	cfg.Command = &ff.Command{
+		Name:      "lock",
+		Usage:     "bine lock",
+		ShortHelp: "Write the lock file with the checksums of all binaries.",
+		LongHelp: `Downloads every binary defined in the configuration file and records the
+resolved tag, download URL, asset name and SHA-256 checksums of the archive and
+the extracted binary in .bine.lock, next to the configuration file. For Go
+packages, it records the "h1:" hash of the module instead.
+
+Once the lock file exists, bine refuses to install binaries whose checksums
+differ from the recorded ones. Only the entries of the current platform are
+updated, so run this command on every platform your project supports.`,
+		Flags: cfg.Flags,
+		Exec:  cfg.Exec,
+	}
+	cfg.RootConfig.Command.Subcommands = append(cfg.RootConfig.Command.Subcommands, cfg.Command)
+	return &cfg
+}
+
+func (cfg *Config) Exec(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		return errors.New("lock accepts no arguments")
+	}
+
+	return cfg.Bine.Lock(ctx)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/peterbourgon/ff/v4"
@@ -14,17 +15,23 @@ import (
 )
 
 type RootConfig struct {
-	Logger         logr.Logger
-	Stdin          io.Reader
-	Stdout         io.Writer
-	Stderr         io.Writer
-	Verbosity      int
-	verboseCount   int
-	CacheDir       string
-	GitHubAPIToken string
-	Flags          *ff.FlagSet
-	Command        *ff.Command
-	Bine           *bine.Bine
+	Logger             logr.Logger
+	Stdin              io.Reader
+	Stdout             io.Writer
+	Stderr             io.Writer
+	Verbosity          int
+	verboseCount       int
+	CacheDir           string
+	GitHubAPIToken     string
+	GitHubAPIURL       string
+	GitHubHostTokens   string
+	GitHubReleasePages int
+	GitLabAPIToken     string
+	Prereleases        bool
+	MaxAge             time.Duration
+	Flags              *ff.FlagSet
+	Command            *ff.Command
+	Bine               *bine.Bine
 }
 
 func New(stdin io.Reader, stdout, stderr io.Writer) *RootConfig {
@@ -37,6 +44,12 @@ func New(stdin io.Reader, stdout, stderr io.Writer) *RootConfig {
 	cfg.Flags.IntVar(&cfg.Verbosity, 0, "verbosity", 0, "Set the log verbosity level explicitly.")
 	cfg.Flags.StringVar(&cfg.CacheDir, 0, "cache-dir", "", "Path to the cache directory.")
 	cfg.Flags.StringVar(&cfg.GitHubAPIToken, 0, "github-api-token", "", "GitHub API token for authentication.")
+	cfg.Flags.StringVar(&cfg.GitHubAPIURL, 0, "github-api-url", "", "GitHub Enterprise Server API URL, e.g. https://ghe.example.com/api/v3.")
+	cfg.Flags.StringVar(&cfg.GitHubHostTokens, 0, "github-host-tokens", "", "GitHub API tokens per host as comma-separated host=token pairs.")
+	cfg.Flags.IntVar(&cfg.GitHubReleasePages, 0, "github-release-pages", 0, "Maximum number of pages of GitHub releases read when looking for the latest version (default 3).")
+	cfg.Flags.StringVar(&cfg.GitLabAPIToken, 0, "gitlab-api-token", "", "GitLab API token for authentication.")
+	cfg.Flags.BoolVar(&cfg.Prereleases, 0, "include-prereleases", "Consider prereleases of every binary when looking for the latest version.")
+	cfg.Flags.DurationVar(&cfg.MaxAge, 0, "max-age", 0, "Use cached version lookups younger than this without revalidating them, e.g. 1h.")
 	cfg.Command = &ff.Command{
 		Name:      "bine",
 		ShortHelp: "Simple binary manager for developers.",
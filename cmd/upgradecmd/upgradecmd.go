@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/peterbourgon/ff/v4"
 
@@ -68,6 +69,11 @@ func (cfg *Config) Exec(ctx context.Context, args []string) error {
 	if err != nil {
 		return err
 	}
+	// Bins up to date within their range are listed for their latest version
+	// out of range, there is nothing to upgrade.
+	updates = slices.DeleteFunc(updates, func(item *bine.ListItem) bool {
+		return item.Latest == "" && item.OutdatedCheckError == ""
+	})
 	if len(updates) == 0 {
 		fmt.Fprintln(cfg.Stdout, "No updates available.")
 		return nil
@@ -0,0 +1,92 @@
+package verifycmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/peterbourgon/ff/v4"
+
+	"github.com/artefactual-labs/bine/bine"
+	"github.com/artefactual-labs/bine/cmd/rootcmd"
+)
+
+type Config struct {
+	*rootcmd.RootConfig
+	Command *ff.Command
+	Flags   *ff.FlagSet
+	JSON    bool
+}
+
+func New(parent *rootcmd.RootConfig) *Config {
+	var cfg Config
+	cfg.RootConfig = parent
+	cfg.Flags = ff.NewFlagSet("verify").SetParent(parent.Flags)
+	cfg.Flags.BoolVar(&cfg.JSON, 0, "json", "Output in JSON format.")
+
+	cfg.Command = &ff.Command{
+		Name:      "verify",
+		Usage:     "bine verify [FLAGS] [NAME]",
+		ShortHelp: "Verify the integrity of the installed binaries.",
+		LongHelp: `Checks every binary in the cache without reinstalling it: the binary must
+exist, its SHA-256 checksum must match the version marker, the version marker
+must match the lock file and binaries installed with "go install" must have
+been built from the configured package and version.
+
+Exits with a non-zero status if any check fails.`,
+		Flags: cfg.Flags,
+		Exec:  cfg.Exec,
+	}
+	cfg.RootConfig.Command.Subcommands = append(cfg.RootConfig.Command.Subcommands, cfg.Command)
+	return &cfg
+}
+
+func (cfg *Config) Exec(ctx context.Context, args []string) error {
+	if len(args) > 1 {
+		return errors.New("verify accepts at most one argument")
+	}
+
+	var (
+		items []*bine.VerifyItem
+		err   error
+	)
+	if len(args) == 1 {
+		items, err = cfg.Bine.VerifyOne(ctx, args[0])
+	} else {
+		items, err = cfg.Bine.Verify(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, item := range items {
+		if !item.OK {
+			failed++
+		}
+	}
+
+	if cfg.JSON {
+		output, err := json.MarshalIndent(items, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cfg.Stdout, string(output))
+	} else {
+		for _, item := range items {
+			if item.OK {
+				fmt.Fprintf(cfg.Stdout, "%s %s ok\n", item.Name, item.Version)
+			} else {
+				fmt.Fprintf(cfg.Stdout, "%s %s FAILED (%s)\n", item.Name, item.Version, strings.Join(item.Problems, "; "))
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("verification failed for %d binaries", failed)
+	}
+
+	return nil
+}
@@ -17,13 +17,16 @@ import (
 	"github.com/artefactual-labs/bine/cmd/configcmd"
 	"github.com/artefactual-labs/bine/cmd/envcmd"
 	"github.com/artefactual-labs/bine/cmd/getcmd"
+	"github.com/artefactual-labs/bine/cmd/importcmd"
 	"github.com/artefactual-labs/bine/cmd/listcmd"
+	"github.com/artefactual-labs/bine/cmd/lockcmd"
 	"github.com/artefactual-labs/bine/cmd/pathcmd"
 	"github.com/artefactual-labs/bine/cmd/reinstallcmd"
 	"github.com/artefactual-labs/bine/cmd/rootcmd"
 	"github.com/artefactual-labs/bine/cmd/runcmd"
 	"github.com/artefactual-labs/bine/cmd/synccmd"
 	"github.com/artefactual-labs/bine/cmd/upgradecmd"
+	"github.com/artefactual-labs/bine/cmd/verifycmd"
 	"github.com/artefactual-labs/bine/cmd/versioncmd"
 )
 
@@ -56,12 +59,15 @@ func exec(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io
 		_    = configcmd.New(root)
 		_    = envcmd.New(root)
 		_    = getcmd.New(root)
+		_    = importcmd.New(root)
 		_    = listcmd.New(root)
+		_    = lockcmd.New(root)
 		_    = pathcmd.New(root)
 		_    = reinstallcmd.New(root)
 		_    = runcmd.New(root)
 		_    = synccmd.New(root)
 		_    = upgradecmd.New(root)
+		_    = verifycmd.New(root)
 		_    = versioncmd.New(root)
 	)
 
@@ -112,6 +118,12 @@ func build(ctx context.Context, logger logr.Logger, root *rootcmd.RootConfig) (*
 		bine.WithCacheDir(root.CacheDir),
 		bine.WithLogger(logger),
 		bine.WithGitHubAPIToken(root.GitHubAPIToken),
+		bine.WithGitHubAPIURL(root.GitHubAPIURL),
+		bine.WithGitHubHostTokens(root.GitHubHostTokens),
+		bine.WithGitLabAPIToken(root.GitLabAPIToken),
+		bine.WithIncludePrereleases(root.Prereleases),
+		bine.WithGitHubReleasePages(root.GitHubReleasePages),
+		bine.WithMaxAge(root.MaxAge),
 	)
 }
 